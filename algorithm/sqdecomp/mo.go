@@ -0,0 +1,170 @@
+package sqdecomp
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// Mo's algorithm
+// 区間の端を1つずつ動かしながら、オフラインで区間クエリに答える
+type Mo interface {
+	// クエリ[l, r)を追加する 追加した順に0, 1, ...の番号が付く
+	AddQuery(l, r int)
+	// addは要素iを区間に加え、removeは要素iを区間から取り除く
+	// 区間がクエリqの区間になったときにanswer(q)が呼ばれる
+	Run(add, remove func(i int), answer func(q int))
+}
+
+// 長さnの列に対するMo
+// クエリはHilbert曲線の順に並べるので、区間の端の移動はO(n√Q)
+func NewMo(n int) Mo {
+	return &mo{n: n}
+}
+
+// 参考にさせていただいた記事:
+// https://codeforces.com/blog/entry/61203
+type mo struct {
+	n      int
+	ls, rs []int
+}
+
+func (m *mo) AddQuery(l, r int) {
+	checkQuery(m.n, l, r)
+	m.ls = append(m.ls, l)
+	m.rs = append(m.rs, r)
+}
+
+func (m *mo) Run(add, remove func(i int), answer func(q int)) {
+	k := bits.Len(uint(m.n))
+	order := make([]int, len(m.ls))
+	keys := make([]uint64, len(m.ls))
+	for q := range order {
+		order[q] = q
+		keys[q] = hilbertOrder(m.ls[q], m.rs[q], k)
+	}
+	slices.SortFunc(order, func(a, b int) int { return cmp.Compare(keys[a], keys[b]) })
+
+	l, r := 0, 0
+	for _, q := range order {
+		l, r = move(l, r, m.ls[q], m.rs[q], add, remove)
+		answer(q)
+	}
+}
+
+// 更新ありのMo
+// 列への1点更新とクエリが混ざっている場合に、時刻も1つの軸として動かす
+type MoWithUpdates interface {
+	// 更新を1つ追加する 追加した順に0, 1, ...の番号が付く
+	AddUpdate()
+	// それまでに追加した更新を全て反映した列に対するクエリ[l, r)を追加する 追加した順に0, 1, ...の番号が付く
+	AddQuery(l, r int)
+	// add, remove, answerはMo.Runと同じ
+	// apply(u, l, r)は更新uを反映し、undo(u, l, r)は更新uを取り消す
+	// [l, r)はそのときの区間で、更新する位置が区間内ならaddとremoveの調整が必要
+	Run(add, remove func(i int), apply, undo func(u, l, r int), answer func(q int))
+}
+
+// 長さnの列に対する更新ありのMo
+// ブロックの大きさは既定でn^(2/3)で、区間の端と時刻の移動はO(n^(5/3))
+func NewMoWithUpdates(n int, opts ...Option) MoWithUpdates {
+	block := newOptions(opts).block
+	if block == 0 {
+		block = max(1, int(math.Cbrt(float64(n)*float64(n))))
+	}
+	return &moWithUpdates{n: n, block: block}
+}
+
+type moWithUpdates struct {
+	n, block   int
+	updates    int
+	ls, rs, ts []int
+}
+
+func (m *moWithUpdates) AddUpdate() {
+	m.updates++
+}
+
+func (m *moWithUpdates) AddQuery(l, r int) {
+	checkQuery(m.n, l, r)
+	m.ls = append(m.ls, l)
+	m.rs = append(m.rs, r)
+	m.ts = append(m.ts, m.updates)
+}
+
+func (m *moWithUpdates) Run(add, remove func(i int), apply, undo func(u, l, r int), answer func(q int)) {
+	order := make([]int, len(m.ls))
+	for q := range order {
+		order[q] = q
+	}
+	// (lのブロック, rのブロック, 時刻)の順に並べる
+	slices.SortFunc(order, func(a, b int) int {
+		if c := cmp.Compare(m.ls[a]/m.block, m.ls[b]/m.block); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(m.rs[a]/m.block, m.rs[b]/m.block); c != 0 {
+			return c
+		}
+		return cmp.Compare(m.ts[a], m.ts[b])
+	})
+
+	l, r, t := 0, 0, 0
+	for _, q := range order {
+		l, r = move(l, r, m.ls[q], m.rs[q], add, remove)
+		for ; t < m.ts[q]; t++ {
+			apply(t, l, r)
+		}
+		for ; t > m.ts[q]; t-- {
+			undo(t-1, l, r)
+		}
+		answer(q)
+	}
+}
+
+// 区間[l, r)を[nl, nr)に動かす 区間が空を下回らないように、広げる方を先にする
+func move(l, r, nl, nr int, add, remove func(i int)) (int, int) {
+	for ; r < nr; r++ {
+		add(r)
+	}
+	for ; l > nl; l-- {
+		add(l - 1)
+	}
+	for ; r > nr; r-- {
+		remove(r - 1)
+	}
+	for ; l < nl; l++ {
+		remove(l)
+	}
+	return l, r
+}
+
+// 2^k×2^kの格子上のHilbert曲線で、(x, y)が何番目に来るか
+func hilbertOrder(x, y, k int) uint64 {
+	n := 1 << k
+	var d uint64
+	for s := n / 2; s > 0; s /= 2 {
+		rx, ry := 0, 0
+		if x&s > 0 {
+			rx = 1
+		}
+		if y&s > 0 {
+			ry = 1
+		}
+		d += uint64(s) * uint64(s) * uint64((3*rx)^ry)
+		if ry == 0 {
+			if rx == 1 {
+				x, y = n-1-x, n-1-y
+			}
+			x, y = y, x
+		}
+	}
+	return d
+}
+
+func checkQuery(n, l, r int) {
+	if l < 0 || r < l || n < r {
+		panic(fmt.Errorf("Mo: invalid query: n=%d, l=%d, r=%d", n, l, r))
+	}
+}
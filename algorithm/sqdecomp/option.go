@@ -0,0 +1,29 @@
+package sqdecomp
+
+import "fmt"
+
+// このパッケージのコンストラクタに渡すオプション
+type Option func(*options)
+
+type options struct {
+	block int // 0なら各コンストラクタの既定値を使う
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// バケットの大きさを指定する
+// 更新と問い合わせの回数が偏っている場合に、速い方の操作の比重を増やすのに使う
+func WithBlockSize(block int) Option {
+	if block <= 0 {
+		panic(fmt.Errorf("sqdecomp: block size must be positive: block=%d", block))
+	}
+	return func(o *options) {
+		o.block = block
+	}
+}
@@ -12,6 +12,12 @@ type RangeSqrtDecomposition[S, F any] interface {
 	Product(l, r int) S
 	Apply(i int, f F)
 	ApplyRange(l, r int, f F)
+	// pred(Product(l, r))がtrueになる最大のrを返す
+	// predはpred(e())==trueかつ単調である必要がある
+	MaxRight(l int, pred func(x S) bool) int
+	// pred(Product(l, r))がtrueになる最小のlを返す
+	// predはpred(e())==trueかつ単調である必要がある
+	MinLeft(r int, pred func(x S) bool) int
 }
 
 func NewRangeSqrtDecomposition[S, F any](
@@ -22,12 +28,13 @@ func NewRangeSqrtDecomposition[S, F any](
 	mapping func(f F, x S) S,
 	mappingBlock func(f F, x S) S,
 	composition func(f, g F) F,
+	opts ...Option,
 ) RangeSqrtDecomposition[S, F] {
 	data := make([]S, n)
 	for i := range data {
 		data[i] = e()
 	}
-	return NewRangeSqrtDecompositionWith(data, e, product, id, mapping, mappingBlock, composition)
+	return NewRangeSqrtDecompositionWith(data, e, product, id, mapping, mappingBlock, composition, opts...)
 }
 
 func NewRangeSqrtDecompositionWith[S, F any](
@@ -38,10 +45,15 @@ func NewRangeSqrtDecompositionWith[S, F any](
 	mapping func(f F, x S) S,
 	mappingBlock func(f F, x S) S,
 	composition func(f, g F) F,
+	opts ...Option,
 ) RangeSqrtDecomposition[S, F] {
 	data = slices.Clone(data)
 	n := len(data)
-	block := int(math.Round(math.Sqrt(float64(n))))
+	// 指定がなければ√n n=0でも0除算しないように1以上にする
+	block := newOptions(opts).block
+	if block == 0 {
+		block = max(1, int(math.Round(math.Sqrt(float64(n)))))
+	}
 	if mappingBlock == nil {
 		mappingBlock = mapping
 	}
@@ -138,20 +150,7 @@ func (sd *rangeSqrtDecomposition[S, F]) Product(l, r int) S {
 		}
 	}
 	for b := lCeil; b < rFloor; b++ {
-		if !sd.isFresh[b] && sd.product != nil {
-			il := b * sd.block
-			ir := min(il+sd.block, sd.n)
-			sd.cache[b] = sd.e()
-			for i := il; i < ir; i++ {
-				sd.cache[b] = sd.product(sd.cache[b], sd.data[i])
-			}
-			sd.isFresh[b] = true
-		}
-		if sd.isWaiting[b] {
-			res = sd.product(res, sd.mappingBlock(sd.lazy[b], sd.cache[b]))
-		} else {
-			res = sd.product(res, sd.cache[b])
-		}
+		res = sd.product(res, sd.blockProduct(b))
 	}
 	if rMin := rFloor * sd.block; rMin < r {
 		sd.evalLazy(rb)
@@ -202,10 +201,80 @@ func (sd *rangeSqrtDecomposition[S, F]) ApplyRange(l, r int, f F) {
 	}
 }
 
+func (sd *rangeSqrtDecomposition[S, F]) MaxRight(l int, pred func(x S) bool) int {
+	acc := sd.e()
+	i := l
+	for i < sd.n {
+		b := sd.nowBlock(i)
+		il := b * sd.block
+		ir := min(il+sd.block, sd.n)
+		// ブロック全体を掛けてもtrueなら、まとめて進める
+		if i == il {
+			if s := sd.product(acc, sd.blockProduct(b)); pred(s) {
+				acc = s
+				i = ir
+				continue
+			}
+		}
+		sd.evalLazy(b)
+		for ; i < ir; i++ {
+			s := sd.product(acc, sd.data[i])
+			if !pred(s) {
+				return i
+			}
+			acc = s
+		}
+	}
+	return sd.n
+}
+
+func (sd *rangeSqrtDecomposition[S, F]) MinLeft(r int, pred func(x S) bool) int {
+	acc := sd.e()
+	i := r
+	for i > 0 {
+		b := sd.nowBlock(i - 1)
+		il := b * sd.block
+		ir := min(il+sd.block, sd.n)
+		if i == ir {
+			if s := sd.product(sd.blockProduct(b), acc); pred(s) {
+				acc = s
+				i = il
+				continue
+			}
+		}
+		sd.evalLazy(b)
+		for ; i > il; i-- {
+			s := sd.product(sd.data[i-1], acc)
+			if !pred(s) {
+				return i
+			}
+			acc = s
+		}
+	}
+	return 0
+}
+
 func (sd *rangeSqrtDecomposition[S, F]) nowBlock(i int) int {
 	return i / sd.block
 }
 
+// ブロックbの要素の積 cacheが古ければ計算し直し、待機しているlazyを作用させる
+func (sd *rangeSqrtDecomposition[S, F]) blockProduct(b int) S {
+	if !sd.isFresh[b] {
+		il := b * sd.block
+		ir := min(il+sd.block, sd.n)
+		sd.cache[b] = sd.e()
+		for i := il; i < ir; i++ {
+			sd.cache[b] = sd.product(sd.cache[b], sd.data[i])
+		}
+		sd.isFresh[b] = true
+	}
+	if sd.isWaiting[b] {
+		return sd.mappingBlock(sd.lazy[b], sd.cache[b])
+	}
+	return sd.cache[b]
+}
+
 func (sd *rangeSqrtDecomposition[S, F]) evalLazy(b int) {
 	if !sd.isWaiting[b] {
 		return
@@ -0,0 +1,171 @@
+package fenwick
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// 加法群として扱える型
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+type FenwickTree[T any] interface {
+	Len() int
+	Add(i int, x T)
+	Set(i int, x T)
+	Get(i int) T
+	// [0, r)の和
+	Sum(r int) T
+	// [l, r)の和
+	RangeSum(l, r int) T
+
+	// pred(Sum(r))がtrueになる最大のrを返す
+	// predはpred(e())==trueかつ単調(Sum(r)が増えるほどfalseになりやすい)である必要がある
+	MaxRight(pred func(sum T) bool) int
+}
+
+func NewFenwickTree[T Number](n int) FenwickTree[T] {
+	return NewFenwickTreeFunc(n, zero[T], add[T], neg[T])
+}
+
+func NewFenwickTreeWith[T Number](s []T) FenwickTree[T] {
+	return NewFenwickTreeWithFunc(s, zero[T], add[T], neg[T])
+}
+
+// modintなど、Numberでない型はe, op, invを渡す
+// eは単位元、opは加算、invは逆元
+func NewFenwickTreeFunc[T any](n int, e func() T, op func(a, b T) T, inv func(a T) T) FenwickTree[T] {
+	data := make([]T, n+1)
+	for i := range data {
+		data[i] = e()
+	}
+	return &fenwickTree[T]{
+		n:    n,
+		data: data,
+		e:    e,
+		op:   op,
+		inv:  inv,
+	}
+}
+
+// O(n)で構築する
+func NewFenwickTreeWithFunc[T any](s []T, e func() T, op func(a, b T) T, inv func(a T) T) FenwickTree[T] {
+	n := len(s)
+	data := make([]T, n+1)
+	data[0] = e()
+	copy(data[1:], s)
+	for i := 1; i <= n; i++ {
+		if j := i + i&-i; j <= n {
+			data[j] = op(data[j], data[i])
+		}
+	}
+	return &fenwickTree[T]{
+		n:    n,
+		data: data,
+		e:    e,
+		op:   op,
+		inv:  inv,
+	}
+}
+
+// LowerBound はSum(i+1) >= wとなる最小のiを返す 存在しなければLen()を返す
+// 要素が全て非負である必要がある
+func LowerBound[T cmp.Ordered](ft FenwickTree[T], w T) int {
+	return ft.MaxRight(func(sum T) bool { return sum < w })
+}
+
+// 参考にさせていただいた記事:
+// https://algo-logic.info/binary-indexed-tree/
+// https://atcoder.github.io/ac-library/master/document_ja/fenwicktree.html
+type fenwickTree[T any] struct {
+	n    int
+	data []T // 1-indexed
+	e    func() T
+	op   func(a, b T) T
+	inv  func(a T) T
+}
+
+func (ft *fenwickTree[T]) Len() int {
+	return ft.n
+}
+
+func (ft *fenwickTree[T]) Add(i int, x T) {
+	ft.checkInRange(i)
+	for i++; i <= ft.n; i += i & -i {
+		ft.data[i] = ft.op(ft.data[i], x)
+	}
+}
+
+func (ft *fenwickTree[T]) Set(i int, x T) {
+	ft.Add(i, ft.op(x, ft.inv(ft.Get(i))))
+}
+
+func (ft *fenwickTree[T]) Get(i int) T {
+	ft.checkInRange(i)
+	return ft.RangeSum(i, i+1)
+}
+
+func (ft *fenwickTree[T]) Sum(r int) T {
+	ft.checkInRangeLR(0, r)
+	res := ft.e()
+	for ; r > 0; r -= r & -r {
+		res = ft.op(res, ft.data[r])
+	}
+	return res
+}
+
+func (ft *fenwickTree[T]) RangeSum(l, r int) T {
+	ft.checkInRangeLR(l, r)
+	return ft.op(ft.Sum(r), ft.inv(ft.Sum(l)))
+}
+
+func (ft *fenwickTree[T]) MaxRight(pred func(sum T) bool) int {
+	r, sum := 0, ft.e()
+	k := 1
+	for k*2 <= ft.n {
+		k *= 2
+	}
+	for ; k > 0; k /= 2 {
+		if r+k <= ft.n {
+			if s := ft.op(sum, ft.data[r+k]); pred(s) {
+				r += k
+				sum = s
+			}
+		}
+	}
+	return r
+}
+
+func (ft *fenwickTree[T]) checkInRange(i int) {
+	if i < 0 || ft.n <= i {
+		panic(fmt.Errorf("FenwickTree: index out of range: n=%d, i=%d", ft.n, i))
+	}
+}
+
+func (ft *fenwickTree[T]) checkInRangeLR(l, r int) {
+	if l < 0 || r < l || ft.n < r {
+		panic(fmt.Errorf("FenwickTree: index out of range: n=%d, l=%d, r=%d", ft.n, l, r))
+	}
+}
+
+func zero[T Number]() T      { return 0 }
+func add[T Number](a, b T) T { return a + b }
+func neg[T Number](a T) T    { return -a }
+
+// xをk回足したものを返す O(log k)
+func times[T any](x T, k int, e func() T, op func(a, b T) T, inv func(a T) T) T {
+	if k < 0 {
+		x, k = inv(x), -k
+	}
+	res := e()
+	for ; k > 0; k >>= 1 {
+		if k&1 > 0 {
+			res = op(res, x)
+		}
+		x = op(x, x)
+	}
+	return res
+}
@@ -0,0 +1,78 @@
+package fenwick
+
+import "fmt"
+
+// h×wの2次元Fenwick Tree
+type FenwickTree2D[T any] interface {
+	Len() (h, w int)
+	Add(i, j int, x T)
+	// [0, i)×[0, j)の和
+	Sum(i, j int) T
+	// [i1, i2)×[j1, j2)の和
+	RangeSum(i1, j1, i2, j2 int) T
+}
+
+func NewFenwickTree2D[T Number](h, w int) FenwickTree2D[T] {
+	return NewFenwickTree2DFunc(h, w, zero[T], add[T], neg[T])
+}
+
+func NewFenwickTree2DFunc[T any](h, w int, e func() T, op func(a, b T) T, inv func(a T) T) FenwickTree2D[T] {
+	data := make([]T, (h+1)*(w+1))
+	for i := range data {
+		data[i] = e()
+	}
+	return &fenwickTree2D[T]{
+		h:    h,
+		w:    w,
+		data: data,
+		e:    e,
+		op:   op,
+		inv:  inv,
+	}
+}
+
+type fenwickTree2D[T any] struct {
+	h, w int
+	data []T // (h+1)×(w+1)を1次元に並べたもの 1-indexed
+	e    func() T
+	op   func(a, b T) T
+	inv  func(a T) T
+}
+
+func (ft *fenwickTree2D[T]) Len() (h, w int) {
+	return ft.h, ft.w
+}
+
+func (ft *fenwickTree2D[T]) Add(i, j int, x T) {
+	if i < 0 || ft.h <= i || j < 0 || ft.w <= j {
+		panic(fmt.Errorf("FenwickTree2D: index out of range: h=%d, w=%d, i=%d, j=%d", ft.h, ft.w, i, j))
+	}
+	for a := i + 1; a <= ft.h; a += a & -a {
+		for b := j + 1; b <= ft.w; b += b & -b {
+			k := a*(ft.w+1) + b
+			ft.data[k] = ft.op(ft.data[k], x)
+		}
+	}
+}
+
+func (ft *fenwickTree2D[T]) Sum(i, j int) T {
+	if i < 0 || ft.h < i || j < 0 || ft.w < j {
+		panic(fmt.Errorf("FenwickTree2D: index out of range: h=%d, w=%d, i=%d, j=%d", ft.h, ft.w, i, j))
+	}
+	res := ft.e()
+	for a := i; a > 0; a -= a & -a {
+		for b := j; b > 0; b -= b & -b {
+			res = ft.op(res, ft.data[a*(ft.w+1)+b])
+		}
+	}
+	return res
+}
+
+func (ft *fenwickTree2D[T]) RangeSum(i1, j1, i2, j2 int) T {
+	if i2 < i1 || j2 < j1 {
+		panic(fmt.Errorf("FenwickTree2D: invalid range: i1=%d, j1=%d, i2=%d, j2=%d", i1, j1, i2, j2))
+	}
+	res := ft.op(ft.Sum(i2, j2), ft.inv(ft.Sum(i1, j2)))
+	res = ft.op(res, ft.inv(ft.Sum(i2, j1)))
+	return ft.op(res, ft.Sum(i1, j1))
+}
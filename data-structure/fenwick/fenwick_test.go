@@ -0,0 +1,109 @@
+package fenwick
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ynm3n/go-cplib/data-structure/segtree"
+)
+
+const benchN = 1 << 18
+
+type benchQuery struct {
+	add  bool
+	i, j int
+	x    int
+}
+
+func genBenchQueries(n, q int) []benchQuery {
+	rnd := rand.New(rand.NewSource(1))
+	res := make([]benchQuery, q)
+	for k := range res {
+		i, j := rnd.Intn(n+1), rnd.Intn(n+1)
+		if i > j {
+			i, j = j, i
+		}
+		res[k] = benchQuery{rnd.Intn(2) == 0, i, j, rnd.Intn(1 << 30)}
+	}
+	return res
+}
+
+func BenchmarkFenwickTree_AddSum(b *testing.B) {
+	qs := genBenchQueries(benchN, benchN)
+	b.ResetTimer()
+	for range b.N {
+		ft := NewFenwickTree[int](benchN)
+		for _, q := range qs {
+			if q.add {
+				ft.Add(q.i%benchN, q.x)
+			} else {
+				_ = ft.RangeSum(q.i, q.j)
+			}
+		}
+	}
+}
+
+func BenchmarkSegmentTree_AddSum(b *testing.B) {
+	qs := genBenchQueries(benchN, benchN)
+	b.ResetTimer()
+	for range b.N {
+		sg := segtree.NewSegmentTree(benchN, func() int { return 0 }, func(a, b int) int { return a + b })
+		for _, q := range qs {
+			if q.add {
+				i := q.i % benchN
+				sg.Set(i, sg.Get(i)+q.x)
+			} else {
+				_ = sg.Product(q.i, q.j)
+			}
+		}
+	}
+}
+
+func BenchmarkFenwickTree_LowerBound(b *testing.B) {
+	qs := genBenchQueries(benchN, benchN)
+	b.ResetTimer()
+	for range b.N {
+		ft := NewFenwickTree[int](benchN)
+		for _, q := range qs {
+			if q.add {
+				ft.Add(q.i%benchN, q.x)
+			} else {
+				_ = LowerBound(ft, q.x)
+			}
+		}
+	}
+}
+
+// SegmentTreeにはMaxRightがないので、Product(0, i+1)を二分探索する O(log^2 n)
+func BenchmarkSegmentTree_LowerBound(b *testing.B) {
+	qs := genBenchQueries(benchN, benchN)
+	b.ResetTimer()
+	for range b.N {
+		sg := segtree.NewSegmentTree(benchN, func() int { return 0 }, func(a, b int) int { return a + b })
+		for _, q := range qs {
+			if q.add {
+				i := q.i % benchN
+				sg.Set(i, sg.Get(i)+q.x)
+			} else {
+				_ = sort.Search(benchN, func(i int) bool { return sg.Product(0, i+1) >= q.x })
+			}
+		}
+	}
+}
+
+// segtreeパッケージには遅延評価のセグメント木がないので、区間加算の比較対象は置いていない
+func BenchmarkRangeAddFenwickTree_AddRangeSum(b *testing.B) {
+	qs := genBenchQueries(benchN, benchN)
+	b.ResetTimer()
+	for range b.N {
+		ft := NewRangeAddFenwickTree[int](benchN)
+		for _, q := range qs {
+			if q.add {
+				ft.AddRange(q.i, q.j, q.x)
+			} else {
+				_ = ft.RangeSum(q.i, q.j)
+			}
+		}
+	}
+}
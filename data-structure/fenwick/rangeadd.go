@@ -0,0 +1,91 @@
+package fenwick
+
+import "fmt"
+
+// 区間加算・区間和ができるFenwick Tree
+type RangeAddFenwickTree[T any] interface {
+	Len() int
+	Add(i int, x T)
+	// [l, r)にxを足す
+	AddRange(l, r int, x T)
+	Get(i int) T
+	// [0, r)の和
+	Sum(r int) T
+	// [l, r)の和
+	RangeSum(l, r int) T
+}
+
+func NewRangeAddFenwickTree[T Number](n int) RangeAddFenwickTree[T] {
+	return NewRangeAddFenwickTreeFunc(n, zero[T], add[T], neg[T])
+}
+
+func NewRangeAddFenwickTreeFunc[T any](n int, e func() T, op func(a, b T) T, inv func(a T) T) RangeAddFenwickTree[T] {
+	return &rangeAddFenwickTree[T]{
+		n:   n,
+		b1:  NewFenwickTreeFunc(n+1, e, op, inv).(*fenwickTree[T]),
+		b2:  NewFenwickTreeFunc(n+1, e, op, inv).(*fenwickTree[T]),
+		e:   e,
+		op:  op,
+		inv: inv,
+	}
+}
+
+// 差分d[i]をb1に、i*d[i]をb2に持つ
+// [0, r)の和 = r*(d[0]+...+d[r-1]) - (0*d[0]+...+(r-1)*d[r-1])
+// 参考にさせていただいた記事:
+// https://algo-logic.info/binary-indexed-tree/
+type rangeAddFenwickTree[T any] struct {
+	n      int
+	b1, b2 *fenwickTree[T]
+	e      func() T
+	op     func(a, b T) T
+	inv    func(a T) T
+}
+
+func (ft *rangeAddFenwickTree[T]) Len() int {
+	return ft.n
+}
+
+func (ft *rangeAddFenwickTree[T]) Add(i int, x T) {
+	ft.checkInRange(i)
+	ft.AddRange(i, i+1, x)
+}
+
+func (ft *rangeAddFenwickTree[T]) AddRange(l, r int, x T) {
+	ft.checkInRangeLR(l, r)
+	ft.b1.Add(l, x)
+	ft.b1.Add(r, ft.inv(x))
+	ft.b2.Add(l, ft.times(x, l))
+	ft.b2.Add(r, ft.times(x, -r))
+}
+
+func (ft *rangeAddFenwickTree[T]) Get(i int) T {
+	ft.checkInRange(i)
+	return ft.RangeSum(i, i+1)
+}
+
+func (ft *rangeAddFenwickTree[T]) Sum(r int) T {
+	ft.checkInRangeLR(0, r)
+	return ft.op(ft.times(ft.b1.Sum(r), r), ft.inv(ft.b2.Sum(r)))
+}
+
+func (ft *rangeAddFenwickTree[T]) RangeSum(l, r int) T {
+	ft.checkInRangeLR(l, r)
+	return ft.op(ft.Sum(r), ft.inv(ft.Sum(l)))
+}
+
+func (ft *rangeAddFenwickTree[T]) times(x T, k int) T {
+	return times(x, k, ft.e, ft.op, ft.inv)
+}
+
+func (ft *rangeAddFenwickTree[T]) checkInRange(i int) {
+	if i < 0 || ft.n <= i {
+		panic(fmt.Errorf("RangeAddFenwickTree: index out of range: n=%d, i=%d", ft.n, i))
+	}
+}
+
+func (ft *rangeAddFenwickTree[T]) checkInRangeLR(l, r int) {
+	if l < 0 || r < l || ft.n < r {
+		panic(fmt.Errorf("RangeAddFenwickTree: index out of range: n=%d, l=%d, r=%d", ft.n, l, r))
+	}
+}
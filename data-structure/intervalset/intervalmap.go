@@ -0,0 +1,134 @@
+package intervalset
+
+import (
+	"fmt"
+
+	"github.com/ynm3n/go-cplib/data-structure/treap"
+)
+
+// 互いに素な半開区間[l, r)それぞれに値を持たせたもの (いわゆるODT, Chtholly Tree)
+// 区間代入が主な操作で、代入の回数に対してならしO(log n)
+// 値が同じでも接する区間はまとめない
+type IntervalMap[V any] interface {
+	// 区間の数
+	Len() int
+	// 区間に含まれる整数の個数
+	Covered() int
+	// [l, r)の値をvにする
+	Assign(l, r int, v V)
+	// [l, r)を取り除く
+	Erase(l, r int)
+	Get(x int) (V, bool)
+	// xを含む区間とその値を返す
+	Find(x int) (l, r int, v V, ok bool)
+	// [l, r)と重なる区間を、[l, r)に切り詰めて昇順に列挙する fnがfalseを返したら止める
+	Range(l, r int, fn func(l, r int, v V) bool)
+	// 全ての区間を昇順に列挙する fnがfalseを返したら止める
+	Ascend(fn func(l, r int, v V) bool)
+}
+
+func NewIntervalMap[V any](opts ...treap.Option) IntervalMap[V] {
+	return &intervalMap[V]{
+		m: treap.NewOrderedMap[int, segment[V]](opts...),
+	}
+}
+
+type intervalMap[V any] struct {
+	m       treap.OrderedMap[int, segment[V]]
+	covered int
+}
+
+// 区間の右端と値 左端はOrderedMapのキー
+type segment[V any] struct {
+	r int
+	v V
+}
+
+func (im *intervalMap[V]) Len() int {
+	return im.m.Len()
+}
+
+func (im *intervalMap[V]) Covered() int {
+	return im.covered
+}
+
+func (im *intervalMap[V]) Assign(l, r int, v V) {
+	im.checkLR(l, r)
+	if l == r {
+		return
+	}
+	im.erase(l, r)
+	im.m.Set(l, segment[V]{r, v})
+	im.covered += r - l
+}
+
+func (im *intervalMap[V]) Erase(l, r int) {
+	im.checkLR(l, r)
+	if l == r {
+		return
+	}
+	im.erase(l, r)
+}
+
+func (im *intervalMap[V]) Get(x int) (V, bool) {
+	_, _, v, ok := im.Find(x)
+	return v, ok
+}
+
+func (im *intervalMap[V]) Find(x int) (l, r int, v V, ok bool) {
+	if k, s, ok := im.m.SearchLeft(x, true); ok && x < s.r {
+		return k, s.r, s.v, true
+	}
+	return 0, 0, *new(V), false
+}
+
+func (im *intervalMap[V]) Range(l, r int, fn func(l, r int, v V) bool) {
+	im.checkLR(l, r)
+	if l == r {
+		return
+	}
+	c := im.m.Seek(l)
+	if _, s, ok := im.m.SearchLeft(l, false); ok && l < s.r {
+		if !fn(l, min(r, s.r), s.v) {
+			return
+		}
+	}
+	for ; c.Valid() && c.Key() < r; c.Next() {
+		s := c.Value()
+		if !fn(c.Key(), min(r, s.r), s.v) {
+			return
+		}
+	}
+}
+
+func (im *intervalMap[V]) Ascend(fn func(l, r int, v V) bool) {
+	for c := im.m.SeekFirst(); c.Valid(); c.Next() {
+		s := c.Value()
+		if !fn(c.Key(), s.r, s.v) {
+			return
+		}
+	}
+}
+
+// xが区間の内側にあれば、xを境に2つに分ける
+func (im *intervalMap[V]) split(x int) {
+	if k, s, ok := im.m.SearchLeft(x, false); ok && x < s.r {
+		im.m.Set(k, segment[V]{x, s.v})
+		im.m.Set(x, s)
+	}
+}
+
+func (im *intervalMap[V]) erase(l, r int) {
+	im.split(l)
+	im.split(r)
+	for c := im.m.Seek(l); c.Valid() && c.Key() < r; {
+		im.covered -= c.Value().r - c.Key()
+		c.Delete()
+	}
+}
+
+func (im *intervalMap[V]) checkLR(l, r int) {
+	if r < l {
+		panic(fmt.Errorf("IntervalMap: invalid range: l=%d, r=%d", l, r))
+	}
+}
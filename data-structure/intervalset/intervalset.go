@@ -0,0 +1,125 @@
+package intervalset
+
+import (
+	"fmt"
+
+	"github.com/ynm3n/go-cplib/data-structure/treap"
+)
+
+// 互いに素な半開区間[l, r)の集合
+// 接する区間や重なる区間はまとめて1つの区間として持つ
+// 各操作はならしO(log n)
+type IntervalSet interface {
+	// 区間の数
+	Len() int
+	// 区間に含まれる整数の個数
+	Covered() int
+	// [l, r)を追加する
+	Insert(l, r int)
+	// [l, r)を取り除く
+	Erase(l, r int)
+	Contains(x int) bool
+	// xを含む区間を返す
+	Find(x int) (l, r int, ok bool)
+	// x以上で、どの区間にも含まれない最小の整数
+	Mex(x int) int
+	// 区間を昇順に列挙する fnがfalseを返したら止める
+	Ascend(fn func(l, r int) bool)
+}
+
+func NewIntervalSet(opts ...treap.Option) IntervalSet {
+	return &intervalSet{
+		m: treap.NewOrderedMap[int, int](opts...),
+	}
+}
+
+// 区間の左端をキー、右端を値として持つ
+type intervalSet struct {
+	m       treap.OrderedMap[int, int]
+	covered int
+}
+
+func (st *intervalSet) Len() int {
+	return st.m.Len()
+}
+
+func (st *intervalSet) Covered() int {
+	return st.covered
+}
+
+func (st *intervalSet) Insert(l, r int) {
+	st.checkLR(l, r)
+	if l == r {
+		return
+	}
+	if k, v, ok := st.m.SearchLeft(l, true); ok && l <= v {
+		l, r = k, max(r, v)
+		st.m.Delete(k)
+		st.covered -= v - k
+	}
+	for c := st.m.Seek(l); c.Valid() && c.Key() <= r; {
+		k, v := c.Key(), c.Value()
+		r = max(r, v)
+		c.Delete()
+		st.covered -= v - k
+	}
+	st.m.Set(l, r)
+	st.covered += r - l
+}
+
+func (st *intervalSet) Erase(l, r int) {
+	st.checkLR(l, r)
+	if l == r {
+		return
+	}
+	if k, v, ok := st.m.SearchLeft(l, false); ok && l < v {
+		st.m.Set(k, l)
+		st.covered -= v - l
+		if r < v {
+			st.m.Set(r, v)
+			st.covered += v - r
+		}
+	}
+	for c := st.m.Seek(l); c.Valid() && c.Key() < r; {
+		k, v := c.Key(), c.Value()
+		c.Delete()
+		st.covered -= v - k
+		if r < v {
+			st.m.Set(r, v)
+			st.covered += v - r
+		}
+	}
+}
+
+func (st *intervalSet) Contains(x int) bool {
+	_, _, ok := st.Find(x)
+	return ok
+}
+
+func (st *intervalSet) Find(x int) (l, r int, ok bool) {
+	if k, v, ok := st.m.SearchLeft(x, true); ok && x < v {
+		return k, v, true
+	}
+	return 0, 0, false
+}
+
+func (st *intervalSet) Mex(x int) int {
+	if _, r, ok := st.Find(x); ok {
+		return r
+	}
+	return x
+}
+
+func (st *intervalSet) Ascend(fn func(l, r int) bool) {
+	for c := st.m.SeekFirst(); c.Valid(); c.Next() {
+		if !fn(c.Key(), c.Value()) {
+			return
+		}
+	}
+}
+
+func (st *intervalSet) checkLR(l, r int) {
+	if r < l {
+		panic(fmt.Errorf("IntervalSet: invalid range: l=%d, r=%d", l, r))
+	}
+}
@@ -0,0 +1,128 @@
+package lichao
+
+import "fmt"
+
+// x∈[l, r)の整数を扱う 最小値を求める
+func NewDynamicLiChaoTree(l, r int) LiChaoTree {
+	return newDynamicLiChaoTree(l, r, func(y1, y2 int) bool { return y1 < y2 })
+}
+
+// x∈[l, r)の整数を扱う 最大値を求める
+func NewDynamicLiChaoTreeMax(l, r int) LiChaoTree {
+	return newDynamicLiChaoTree(l, r, func(y1, y2 int) bool { return y1 > y2 })
+}
+
+func newDynamicLiChaoTree(l, r int, better func(y1, y2 int) bool) *dynamicLiChaoTree {
+	return &dynamicLiChaoTree{
+		l:      l,
+		r:      r,
+		root:   nil,
+		better: better,
+	}
+}
+
+// ポインタを使う 必要な部分だけ作るやつ
+type dynamicLiChaoTree struct {
+	l, r   int
+	root   *node
+	better func(y1, y2 int) bool // y1がy2より良いかどうか
+}
+
+type node struct {
+	ln      line
+	hasLine bool // 線分が担当区間の一部にしか無い場合、直線を持たないノードができる
+	l, r    *node
+}
+
+func (lc *dynamicLiChaoTree) AddLine(a, b int) {
+	lc.AddSegment(lc.l, lc.r, a, b)
+}
+
+func (lc *dynamicLiChaoTree) AddSegment(l, r, a, b int) {
+	if l < lc.l || lc.r < r || r < l {
+		panic(fmt.Errorf("DynamicLiChaoTree: index out of range: l=%d, r=%d, argL=%d, argR=%d", lc.l, lc.r, l, r))
+	}
+	if l < r {
+		lc.root = lc.addSegment(lc.root, lc.l, lc.r, l, r, line{a, b})
+	}
+}
+
+func (lc *dynamicLiChaoTree) Query(x int) (int, bool) {
+	if x < lc.l || lc.r <= x {
+		panic(fmt.Errorf("DynamicLiChaoTree: index out of range: l=%d, r=%d, x=%d", lc.l, lc.r, x))
+	}
+	res, found := 0, false
+	for n, l, r := lc.root, lc.l, lc.r; n != nil; {
+		if n.hasLine {
+			if y := n.ln.eval(x); !found || lc.better(y, res) {
+				res, found = y, true
+			}
+		}
+		if m := mid(l, r); x < m {
+			n, r = n.l, m
+		} else {
+			n, l = n.r, m
+		}
+	}
+	return res, found
+}
+
+func (lc *dynamicLiChaoTree) addSegment(n *node, l, r, argL, argR int, ln line) *node {
+	if argR <= l || r <= argL {
+		return n
+	}
+	if argL <= l && r <= argR {
+		return lc.addLine(n, l, r, ln)
+	}
+	if n == nil {
+		n = &node{}
+	}
+	m := mid(l, r)
+	n.l = lc.addSegment(n.l, l, m, argL, argR, ln)
+	n.r = lc.addSegment(n.r, m, r, argL, argR, ln)
+	return n
+}
+
+func (lc *dynamicLiChaoTree) addLine(n *node, l, r int, ln line) *node {
+	if n == nil {
+		return &node{ln: ln, hasLine: true}
+	}
+	for now := n; ; {
+		if !now.hasLine {
+			now.ln, now.hasLine = ln, true
+			return n
+		}
+		m := mid(l, r)
+		cur := now.ln
+		bl := lc.better(ln.eval(l), cur.eval(l))
+		bm := lc.better(ln.eval(m), cur.eval(m))
+		br := lc.better(ln.eval(r-1), cur.eval(r-1))
+		if bm {
+			now.ln, ln = ln, cur
+		}
+		if r-l == 1 {
+			return n
+		}
+		switch {
+		case bl != bm:
+			if now.l == nil {
+				now.l = &node{ln: ln, hasLine: true}
+				return n
+			}
+			now, r = now.l, m
+		case br != bm:
+			if now.r == nil {
+				now.r = &node{ln: ln, hasLine: true}
+				return n
+			}
+			now, l = now.r, m
+		default:
+			return n
+		}
+	}
+}
+
+// 負の座標でも区間が縮むように切り捨てで計算する
+func mid(l, r int) int {
+	return l + (r-l)/2
+}
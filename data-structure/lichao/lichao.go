@@ -0,0 +1,177 @@
+package lichao
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// 直線(線分) y = ax + b の集合に対して、xでの最小値(最大値)を求める
+type LiChaoTree interface {
+	// 直線 y = ax + b を追加する
+	AddLine(a, b int)
+	// x∈[l, r)の範囲だけに線分 y = ax + b を追加する
+	AddSegment(l, r, a, b int)
+	// xでの最小値(最大値)を返す xを通る直線が無ければfalse
+	Query(x int) (int, bool)
+}
+
+// xsにはQueryで使うx座標を全て含める 最小値を求める
+func NewLiChaoTree(xs []int) LiChaoTree {
+	return newLiChaoTree(xs, func(y1, y2 int) bool { return y1 < y2 })
+}
+
+// xsにはQueryで使うx座標を全て含める 最大値を求める
+func NewLiChaoTreeMax(xs []int) LiChaoTree {
+	return newLiChaoTree(xs, func(y1, y2 int) bool { return y1 > y2 })
+}
+
+func newLiChaoTree(xs []int, better func(y1, y2 int) bool) *liChaoTree {
+	xs = slices.Clone(xs)
+	slices.Sort(xs)
+	xs = slices.Compact(xs)
+	size := max(len(xs), 1) * 4
+	return &liChaoTree{
+		xs:      xs,
+		lines:   make([]line, size),
+		hasLine: make([]bool, size),
+		better:  better,
+	}
+}
+
+// 座標圧縮したx上のセグメントツリーの各ノードに、
+// 担当区間の中央で最も良い直線を1本ずつ持たせる
+// 参考にさせていただいた記事:
+// https://smijake3.hatenablog.com/entry/2018/06/16/144548
+type liChaoTree struct {
+	xs      []int // Queryで使うx座標 (ソート済み)
+	lines   []line
+	hasLine []bool
+	better  func(y1, y2 int) bool // y1がy2より良いかどうか
+}
+
+func (lc *liChaoTree) AddLine(a, b int) {
+	if len(lc.xs) == 0 {
+		return
+	}
+	lc.addLine(1, 0, len(lc.xs), line{a, b})
+}
+
+func (lc *liChaoTree) AddSegment(l, r, a, b int) {
+	if r < l {
+		panic(fmt.Errorf("LiChaoTree: invalid range: l=%d, r=%d", l, r))
+	}
+	il, _ := slices.BinarySearch(lc.xs, l)
+	ir, _ := slices.BinarySearch(lc.xs, r)
+	if il < ir {
+		lc.addSegment(1, 0, len(lc.xs), il, ir, line{a, b})
+	}
+}
+
+func (lc *liChaoTree) Query(x int) (int, bool) {
+	i, ok := slices.BinarySearch(lc.xs, x)
+	if !ok {
+		panic(fmt.Errorf("LiChaoTree: x is not registered: x=%d", x))
+	}
+	res, found := 0, false
+	for k, l, r := 1, 0, len(lc.xs); ; {
+		if lc.hasLine[k] {
+			if y := lc.lines[k].eval(x); !found || lc.better(y, res) {
+				res, found = y, true
+			}
+		}
+		if r-l == 1 {
+			break
+		}
+		if m := (l + r) / 2; i < m {
+			k, r = k*2, m
+		} else {
+			k, l = k*2+1, m
+		}
+	}
+	return res, found
+}
+
+func (lc *liChaoTree) addLine(k, l, r int, ln line) {
+	for {
+		if !lc.hasLine[k] {
+			lc.lines[k], lc.hasLine[k] = ln, true
+			return
+		}
+		m := (l + r) / 2
+		cur := lc.lines[k]
+		bl := lc.better(ln.eval(lc.xs[l]), cur.eval(lc.xs[l]))
+		bm := lc.better(ln.eval(lc.xs[m]), cur.eval(lc.xs[m]))
+		br := lc.better(ln.eval(lc.xs[r-1]), cur.eval(lc.xs[r-1]))
+		if bm {
+			lc.lines[k], ln = ln, cur
+		}
+		if r-l == 1 {
+			return
+		}
+		switch {
+		case bl != bm:
+			k, r = k*2, m
+		case br != bm:
+			k, l = k*2+1, m
+		default:
+			return
+		}
+	}
+}
+
+func (lc *liChaoTree) addSegment(k, l, r, argL, argR int, ln line) {
+	if argR <= l || r <= argL {
+		return
+	}
+	if argL <= l && r <= argR {
+		lc.addLine(k, l, r, ln)
+		return
+	}
+	m := (l + r) / 2
+	lc.addSegment(k*2, l, m, argL, argR, ln)
+	lc.addSegment(k*2+1, m, r, argL, argR, ln)
+}
+
+// y = ax + b
+type line struct {
+	a, b int
+}
+
+// オーバーフローする場合はmath.MaxIntかmath.MinIntに丸める
+func (ln line) eval(x int) int {
+	return addSat(mulSat(ln.a, x), ln.b)
+}
+
+func mulSat(a, b int) int {
+	neg := (a < 0) != (b < 0)
+	ua, ub := uint64(a), uint64(b)
+	if a < 0 {
+		ua = -ua
+	}
+	if b < 0 {
+		ub = -ub
+	}
+	hi, lo := bits.Mul64(ua, ub)
+	switch {
+	case neg && (hi != 0 || lo > 1<<63):
+		return math.MinInt
+	case !neg && (hi != 0 || lo > math.MaxInt):
+		return math.MaxInt
+	case neg:
+		return int(-lo)
+	}
+	return int(lo)
+}
+
+func addSat(a, b int) int {
+	s := a + b
+	switch {
+	case a > 0 && b > 0 && s < 0:
+		return math.MaxInt
+	case a < 0 && b < 0 && s >= 0:
+		return math.MinInt
+	}
+	return s
+}
@@ -0,0 +1,168 @@
+package priorityqueue
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// [0, n)のidを付けて要素を入れる優先度付きキュー
+// idを指定して値の変更や削除ができるので、DijkstraやPrimで遅延削除をしなくてよい
+type IndexedPriorityQueue[T any] interface {
+	Len() int
+	// 先頭の要素のidと値
+	Top() (int, T)
+	// idがすでに入っている場合はpanic
+	Enqueue(id int, x T)
+	Dequeue() (int, T)
+	// idの値をxに変える idが入っていない場合はpanic
+	Update(id int, x T)
+	// idの要素を取り除いて値を返す idが入っていない場合はpanic
+	Remove(id int) T
+	Contains(id int) bool
+	// idの値 idが入っていない場合はpanic
+	Get(id int) T
+}
+
+// 昇順
+func NewIndexedPriorityQueue[T cmp.Ordered](n int) IndexedPriorityQueue[T] {
+	return NewIndexedPriorityQueueFunc(n, cmp.Less[T])
+}
+
+func NewIndexedPriorityQueueFunc[T any](n int, less func(a, b T) bool) IndexedPriorityQueue[T] {
+	pq := new(indexedPriorityQueue[T])
+	pq.s = make([]int, 0)
+	pq.vals = make([]T, n)
+	pq.pos = make([]int, n)
+	for i := range pq.pos {
+		pq.pos[i] = -1
+	}
+	pq.less = less
+	return pq
+}
+
+// ヒープにはidを並べ、各idのヒープ内の位置をposで持つ
+type indexedPriorityQueue[T any] struct {
+	s    []int
+	vals []T
+	pos  []int // 入っていなければ-1
+	less func(a, b T) bool
+}
+
+func (pq *indexedPriorityQueue[T]) Len() int { return len(pq.s) }
+
+func (pq *indexedPriorityQueue[T]) Top() (int, T) {
+	pq.checkNotEmpty()
+	id := pq.s[0]
+	return id, pq.vals[id]
+}
+
+func (pq *indexedPriorityQueue[T]) Enqueue(id int, x T) {
+	pq.checkID(id)
+	if pq.pos[id] >= 0 {
+		panic(fmt.Errorf("IndexedPriorityQueue: id %d is already in the queue", id))
+	}
+	pq.vals[id] = x
+	pq.s = append(pq.s, id)
+	pq.up(len(pq.s) - 1)
+}
+
+func (pq *indexedPriorityQueue[T]) Dequeue() (int, T) {
+	pq.checkNotEmpty()
+	id := pq.s[0]
+	pq.removeAt(0)
+	return id, pq.vals[id]
+}
+
+func (pq *indexedPriorityQueue[T]) Update(id int, x T) {
+	pq.checkContains(id)
+	pq.vals[id] = x
+	pq.up(pq.pos[id])
+	pq.down(pq.pos[id])
+}
+
+func (pq *indexedPriorityQueue[T]) Remove(id int) T {
+	pq.checkContains(id)
+	pq.removeAt(pq.pos[id])
+	return pq.vals[id]
+}
+
+func (pq *indexedPriorityQueue[T]) Contains(id int) bool {
+	pq.checkID(id)
+	return pq.pos[id] >= 0
+}
+
+func (pq *indexedPriorityQueue[T]) Get(id int) T {
+	pq.checkContains(id)
+	return pq.vals[id]
+}
+
+func (pq *indexedPriorityQueue[T]) checkNotEmpty() {
+	if len(pq.s) == 0 {
+		panic(fmt.Errorf("IndexedPriorityQueue: queue is empty"))
+	}
+}
+
+func (pq *indexedPriorityQueue[T]) checkID(id int) {
+	if id < 0 || len(pq.pos) <= id {
+		panic(fmt.Errorf("IndexedPriorityQueue: id out of range: n=%d, id=%d", len(pq.pos), id))
+	}
+}
+
+func (pq *indexedPriorityQueue[T]) checkContains(id int) {
+	if !pq.Contains(id) {
+		panic(fmt.Errorf("IndexedPriorityQueue: id %d is not in the queue", id))
+	}
+}
+
+// ヒープのi番目を取り除き、最後の要素をそこに移して直す
+func (pq *indexedPriorityQueue[T]) removeAt(i int) {
+	n := len(pq.s) - 1
+	pq.pos[pq.s[i]] = -1
+	last := pq.s[n]
+	pq.s = pq.s[:n]
+	if i == n {
+		return
+	}
+	pq.s[i] = last
+	pq.pos[last] = i
+	pq.up(i)
+	pq.down(pq.pos[last])
+}
+
+// idを動かすたびにposも書き換える
+func (pq *indexedPriorityQueue[T]) up(i int) {
+	id := pq.s[i]
+	for i > 0 {
+		p := (i - 1) / 2
+		if !pq.less(pq.vals[id], pq.vals[pq.s[p]]) {
+			break
+		}
+		pq.s[i] = pq.s[p]
+		pq.pos[pq.s[i]] = i
+		i = p
+	}
+	pq.s[i] = id
+	pq.pos[id] = i
+}
+
+func (pq *indexedPriorityQueue[T]) down(i int) {
+	n := len(pq.s)
+	id := pq.s[i]
+	for {
+		c := 2*i + 1
+		if c >= n {
+			break
+		}
+		if c+1 < n && pq.less(pq.vals[pq.s[c+1]], pq.vals[pq.s[c]]) {
+			c++
+		}
+		if !pq.less(pq.vals[pq.s[c]], pq.vals[id]) {
+			break
+		}
+		pq.s[i] = pq.s[c]
+		pq.pos[pq.s[i]] = i
+		i = c
+	}
+	pq.s[i] = id
+	pq.pos[id] = i
+}
@@ -0,0 +1,176 @@
+package priorityqueue
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// 左偏木 MeldはO(log n)
+func NewLeftistHeap[T cmp.Ordered]() MeldablePriorityQueue[T] {
+	return NewLeftistHeapFunc(cmp.Less[T])
+}
+
+func NewLeftistHeapFunc[T any](less func(a, b T) bool) MeldablePriorityQueue[T] {
+	return &leftistHeap[T]{less: less}
+}
+
+// 永続左偏木 各操作でO(log n)個のノードを作る
+// k番目の最短経路などで、同じ部分木を共有するヒープを大量に作るときに使う
+func NewPersistentLeftistHeap[T cmp.Ordered]() PersistentPriorityQueue[T] {
+	return NewPersistentLeftistHeapFunc(cmp.Less[T])
+}
+
+func NewPersistentLeftistHeapFunc[T any](less func(a, b T) bool) PersistentPriorityQueue[T] {
+	return persistentLeftistHeap[T]{less: less}
+}
+
+type leftistNode[T any] struct {
+	x    T
+	rank int // 右の子をたどって葉に着くまでのノード数
+	size int
+	l, r *leftistNode[T]
+}
+
+func (nd *leftistNode[T]) getRank() int {
+	if nd == nil {
+		return 0
+	}
+	return nd.rank
+}
+
+func (nd *leftistNode[T]) getSize() int {
+	if nd == nil {
+		return 0
+	}
+	return nd.size
+}
+
+// 右の子の方がrankが小さくなるように子を入れ替え、rankとsizeを計算し直す
+func (nd *leftistNode[T]) update() {
+	if nd.l.getRank() < nd.r.getRank() {
+		nd.l, nd.r = nd.r, nd.l
+	}
+	nd.rank = nd.r.getRank() + 1
+	nd.size = nd.l.getSize() + 1 + nd.r.getSize()
+}
+
+type leftistHeap[T any] struct {
+	root *leftistNode[T]
+	less func(a, b T) bool
+}
+
+func (h *leftistHeap[T]) Len() int {
+	return h.root.getSize()
+}
+
+func (h *leftistHeap[T]) Top() T {
+	h.checkNotEmpty()
+	return h.root.x
+}
+
+func (h *leftistHeap[T]) TryTop() (T, bool) {
+	if h.root == nil {
+		return *new(T), false
+	}
+	return h.root.x, true
+}
+
+func (h *leftistHeap[T]) Enqueue(x T) {
+	h.root = h.meld(h.root, &leftistNode[T]{x: x, rank: 1, size: 1})
+}
+
+func (h *leftistHeap[T]) Dequeue() T {
+	h.checkNotEmpty()
+	res := h.root.x
+	h.root = h.meld(h.root.l, h.root.r)
+	return res
+}
+
+func (h *leftistHeap[T]) TryDequeue() (T, bool) {
+	if h.root == nil {
+		return *new(T), false
+	}
+	return h.Dequeue(), true
+}
+
+func (h *leftistHeap[T]) Meld(other MeldablePriorityQueue[T]) {
+	o := other.(*leftistHeap[T])
+	if o == h {
+		return
+	}
+	h.root = h.meld(h.root, o.root)
+	o.root = nil
+}
+
+// 右の子の列は長さO(log n)なので、再帰が深くならない
+func (h *leftistHeap[T]) meld(a, b *leftistNode[T]) *leftistNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if h.less(b.x, a.x) {
+		a, b = b, a
+	}
+	a.r = h.meld(a.r, b)
+	a.update()
+	return a
+}
+
+func (h *leftistHeap[T]) checkNotEmpty() {
+	if h.root == nil {
+		panic(fmt.Errorf("LeftistHeap: queue is empty"))
+	}
+}
+
+type persistentLeftistHeap[T any] struct {
+	root *leftistNode[T]
+	less func(a, b T) bool
+}
+
+func (h persistentLeftistHeap[T]) Len() int {
+	return h.root.getSize()
+}
+
+func (h persistentLeftistHeap[T]) Top() T {
+	h.checkNotEmpty()
+	return h.root.x
+}
+
+func (h persistentLeftistHeap[T]) Enqueue(x T) PersistentPriorityQueue[T] {
+	return persistentLeftistHeap[T]{h.meld(h.root, &leftistNode[T]{x: x, rank: 1, size: 1}), h.less}
+}
+
+func (h persistentLeftistHeap[T]) Dequeue() (T, PersistentPriorityQueue[T]) {
+	h.checkNotEmpty()
+	return h.root.x, persistentLeftistHeap[T]{h.meld(h.root.l, h.root.r), h.less}
+}
+
+func (h persistentLeftistHeap[T]) Meld(other PersistentPriorityQueue[T]) PersistentPriorityQueue[T] {
+	o := other.(persistentLeftistHeap[T])
+	return persistentLeftistHeap[T]{h.meld(h.root, o.root), h.less}
+}
+
+// 通る経路上のノードだけ複製する
+func (h persistentLeftistHeap[T]) meld(a, b *leftistNode[T]) *leftistNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if h.less(b.x, a.x) {
+		a, b = b, a
+	}
+	nd := *a
+	nd.r = h.meld(a.r, b)
+	nd.update()
+	return &nd
+}
+
+func (h persistentLeftistHeap[T]) checkNotEmpty() {
+	if h.root == nil {
+		panic(fmt.Errorf("PersistentLeftistHeap: queue is empty"))
+	}
+}
@@ -0,0 +1,20 @@
+package priorityqueue
+
+// 2つのキューを併合できる優先度付きキュー
+type MeldablePriorityQueue[T any] interface {
+	PriorityQueue[T]
+	// otherの要素を全て移す otherは同じ種類・同じ比較関数のキューである必要があり、操作後は空になる
+	// otherが自分自身のときは何もしない
+	Meld(other MeldablePriorityQueue[T])
+}
+
+// 永続な優先度付きキュー 操作は元のキューを変えずに新しいキューを返す
+type PersistentPriorityQueue[T any] interface {
+	Len() int
+	Top() T
+	Enqueue(x T) PersistentPriorityQueue[T]
+	// 先頭の要素と、それを取り除いたキューを返す
+	Dequeue() (T, PersistentPriorityQueue[T])
+	// otherは同じ種類・同じ比較関数のキューである必要がある
+	Meld(other PersistentPriorityQueue[T]) PersistentPriorityQueue[T]
+}
@@ -0,0 +1,179 @@
+package priorityqueue
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// 最小値と最大値の両方を取り出せるヒープ
+type MinMaxHeap[T any] interface {
+	Len() int
+	Push(x T)
+	Min() T
+	Max() T
+	PopMin() T
+	PopMax() T
+}
+
+// 昇順
+func NewMinMaxHeap[T cmp.Ordered]() MinMaxHeap[T] {
+	return NewMinMaxHeapFunc(cmp.Less[T])
+}
+
+func NewMinMaxHeapFunc[T any](less func(a, b T) bool) MinMaxHeap[T] {
+	return &intervalHeap[T]{
+		s:    make([]T, 0),
+		less: less,
+	}
+}
+
+// interval heap
+// ノードkがs[2k](区間の左端)とs[2k+1](区間の右端)を持ち、子の区間は親の区間に含まれる
+// 最後のノードだけ要素が1つのことがある
+type intervalHeap[T any] struct {
+	s    []T
+	less func(a, b T) bool
+}
+
+func (h *intervalHeap[T]) Len() int {
+	return len(h.s)
+}
+
+func (h *intervalHeap[T]) Push(x T) {
+	h.s = append(h.s, x)
+	i := len(h.s) - 1
+	if i%2 == 1 && h.less(h.s[i], h.s[i-1]) {
+		h.s[i-1], h.s[i] = h.s[i], h.s[i-1]
+		i--
+	}
+	// 要素が1つのノードは、左端としても右端としても扱う
+	h.upMin(i)
+	h.upMax(i)
+}
+
+func (h *intervalHeap[T]) Min() T {
+	h.checkNotEmpty()
+	return h.s[0]
+}
+
+func (h *intervalHeap[T]) Max() T {
+	h.checkNotEmpty()
+	if len(h.s) == 1 {
+		return h.s[0]
+	}
+	return h.s[1]
+}
+
+func (h *intervalHeap[T]) PopMin() T {
+	h.checkNotEmpty()
+	res := h.s[0]
+	n := len(h.s) - 1
+	h.s[0] = h.s[n]
+	h.s = h.s[:n]
+	if n > 0 {
+		h.downMin(0)
+	}
+	return res
+}
+
+func (h *intervalHeap[T]) PopMax() T {
+	h.checkNotEmpty()
+	if len(h.s) == 1 {
+		return h.PopMin()
+	}
+	res := h.s[1]
+	n := len(h.s) - 1
+	h.s[1] = h.s[n]
+	h.s = h.s[:n]
+	if n > 1 {
+		h.downMax(1)
+	}
+	return res
+}
+
+func (h *intervalHeap[T]) swap(i, j int) {
+	h.s[i], h.s[j] = h.s[j], h.s[i]
+}
+
+func (h *intervalHeap[T]) upMin(i int) {
+	for k := i / 2; k > 0; k = (k - 1) / 2 {
+		p := (k - 1) / 2 * 2
+		if !h.less(h.s[i], h.s[p]) {
+			return
+		}
+		h.swap(i, p)
+		i = p
+	}
+}
+
+func (h *intervalHeap[T]) upMax(i int) {
+	for k := i / 2; k > 0; k = (k - 1) / 2 {
+		p := (k-1)/2*2 + 1
+		if !h.less(h.s[p], h.s[i]) {
+			return
+		}
+		h.swap(i, p)
+		i = p
+	}
+}
+
+func (h *intervalHeap[T]) downMin(i int) {
+	n := len(h.s)
+	for {
+		// 子ノードの左端のうち小さい方
+		c := i*2 + 2
+		if c >= n {
+			return
+		}
+		if c+2 < n && h.less(h.s[c+2], h.s[c]) {
+			c += 2
+		}
+		if !h.less(h.s[c], h.s[i]) {
+			return
+		}
+		h.swap(i, c)
+		if c+1 < n && h.less(h.s[c+1], h.s[c]) {
+			h.swap(c, c+1)
+		}
+		i = c
+	}
+}
+
+func (h *intervalHeap[T]) downMax(i int) {
+	n := len(h.s)
+	for {
+		// 子ノードの右端のうち大きい方 要素が1つのノードはその要素を右端とみなす
+		c := i*2 + 1
+		if c-1 >= n {
+			return
+		}
+		if c >= n {
+			c--
+		}
+		if d := i*2 + 3; d-1 < n {
+			if d >= n {
+				d--
+			}
+			if h.less(h.s[c], h.s[d]) {
+				c = d
+			}
+		}
+		if !h.less(h.s[i], h.s[c]) {
+			return
+		}
+		h.swap(i, c)
+		if c%2 == 0 {
+			return
+		}
+		if h.less(h.s[c], h.s[c-1]) {
+			h.swap(c-1, c)
+		}
+		i = c
+	}
+}
+
+func (h *intervalHeap[T]) checkNotEmpty() {
+	if len(h.s) == 0 {
+		panic(fmt.Errorf("MinMaxHeap: heap is empty"))
+	}
+}
@@ -0,0 +1,122 @@
+package priorityqueue
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// pairing heap Enqueue/MeldはO(1)、DequeueはならしO(log n)
+func NewPairingHeap[T cmp.Ordered]() MeldablePriorityQueue[T] {
+	return NewPairingHeapFunc(cmp.Less[T])
+}
+
+func NewPairingHeapFunc[T any](less func(a, b T) bool) MeldablePriorityQueue[T] {
+	return &pairingHeap[T]{less: less}
+}
+
+type pairingHeap[T any] struct {
+	root *pairingNode[T]
+	len  int
+	less func(a, b T) bool
+}
+
+// 子は連結リストで持つ
+type pairingNode[T any] struct {
+	x              T
+	child, sibling *pairingNode[T]
+}
+
+func (h *pairingHeap[T]) Len() int {
+	return h.len
+}
+
+func (h *pairingHeap[T]) Top() T {
+	h.checkNotEmpty()
+	return h.root.x
+}
+
+func (h *pairingHeap[T]) TryTop() (T, bool) {
+	if h.root == nil {
+		return *new(T), false
+	}
+	return h.root.x, true
+}
+
+func (h *pairingHeap[T]) Enqueue(x T) {
+	h.root = h.meld(h.root, &pairingNode[T]{x: x})
+	h.len++
+}
+
+func (h *pairingHeap[T]) Dequeue() T {
+	h.checkNotEmpty()
+	res := h.root.x
+	h.root = h.mergePairs(h.root.child)
+	h.len--
+	return res
+}
+
+func (h *pairingHeap[T]) TryDequeue() (T, bool) {
+	if h.root == nil {
+		return *new(T), false
+	}
+	return h.Dequeue(), true
+}
+
+func (h *pairingHeap[T]) Meld(other MeldablePriorityQueue[T]) {
+	o := other.(*pairingHeap[T])
+	if o == h {
+		return
+	}
+	h.root = h.meld(h.root, o.root)
+	h.len += o.len
+	o.root, o.len = nil, 0
+}
+
+// 根の小さい方に、もう一方を子として付ける
+func (h *pairingHeap[T]) meld(a, b *pairingNode[T]) *pairingNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if h.less(b.x, a.x) {
+		a, b = b, a
+	}
+	b.sibling = a.child
+	a.child = b
+	return a
+}
+
+// 兄弟を左から2つずつ併合し、それらを右から順に併合する
+// 子の数が多くても再帰しないように、1回目の結果はsiblingでつないだスタックに積む
+func (h *pairingHeap[T]) mergePairs(nd *pairingNode[T]) *pairingNode[T] {
+	var stack *pairingNode[T]
+	for nd != nil {
+		a, b := nd, nd.sibling
+		if b == nil {
+			nd = nil
+		} else {
+			nd = b.sibling
+			b.sibling = nil
+		}
+		a.sibling = nil
+		m := h.meld(a, b)
+		m.sibling = stack
+		stack = m
+	}
+	var res *pairingNode[T]
+	for stack != nil {
+		m := stack
+		stack = m.sibling
+		m.sibling = nil
+		res = h.meld(res, m)
+	}
+	return res
+}
+
+func (h *pairingHeap[T]) checkNotEmpty() {
+	if h.root == nil {
+		panic(fmt.Errorf("PairingHeap: queue is empty"))
+	}
+}
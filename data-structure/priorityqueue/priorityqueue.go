@@ -2,49 +2,230 @@ package priorityqueue
 
 import (
 	"cmp"
-	"container/heap"
+	"fmt"
 )
 
 type PriorityQueue[T any] interface {
 	Len() int
+	// 空ならpanic
 	Top() T
 	Enqueue(x T)
+	// 空ならpanic
 	Dequeue() T
+	// 空ならfalseを返す
+	TryTop() (T, bool)
+	TryDequeue() (T, bool)
+}
+
+// スライスで持つヒープ PriorityQueueに加えて、まとめて入れる・見る・取り出す操作ができる
+type Heap[T any] interface {
+	PriorityQueue[T]
+	// 中身をsの要素に置き換える O(len(s))
+	Init(s []T)
+	Clear()
+	// 先頭からk個を順に返す 取り除かない O(k log k)
+	PeekN(k int) []T
+	// 全ての要素を順に取り出して返す
+	DrainSorted() []T
 }
 
 // 昇順
-func NewPriorityQueue[T cmp.Ordered]() PriorityQueue[T] {
+func NewPriorityQueue[T cmp.Ordered]() Heap[T] {
 	pq := NewPriorityQueueFunc(cmp.Less[T])
 	return pq
 }
 
 // cmp.Less関数を参考にlessを実装する
 // 参考: https://pkg.go.dev/cmp@go1.22.0#Less
-func NewPriorityQueueFunc[T any](less func(a, b T) bool) PriorityQueue[T] {
+func NewPriorityQueueFunc[T any](less func(a, b T) bool) Heap[T] {
+	return newPriorityQueue(less, 1)
+}
+
+// O(len(s))で構築する
+func NewPriorityQueueWith[T cmp.Ordered](s []T) Heap[T] {
+	return NewPriorityQueueWithFunc(s, cmp.Less[T])
+}
+
+func NewPriorityQueueWithFunc[T any](s []T, less func(a, b T) bool) Heap[T] {
+	pq := NewPriorityQueueFunc(less)
+	pq.Init(s)
+	return pq
+}
+
+// 4分木のヒープ 木が浅くなるので、要素が多くDequeueが多い場合に速いことがある
+func NewQuaternaryPriorityQueue[T cmp.Ordered]() Heap[T] {
+	return NewQuaternaryPriorityQueueFunc(cmp.Less[T])
+}
+
+func NewQuaternaryPriorityQueueFunc[T any](less func(a, b T) bool) Heap[T] {
+	return newPriorityQueue(less, 2)
+}
+
+// 大きい方からk個の要素だけを持つ(昇順)
+// Topはその中で最小の要素、つまりこれまでに入れた中でk番目に大きい要素
+func NewTopKPriorityQueue[T cmp.Ordered](k int) Heap[T] {
+	return NewTopKPriorityQueueFunc(k, cmp.Less[T])
+}
+
+// lessで並べたときに後ろからk個の要素だけを持つ 要素数がkを超えたら先頭を捨てる
+func NewTopKPriorityQueueFunc[T any](k int, less func(a, b T) bool) Heap[T] {
+	if k <= 0 {
+		panic(fmt.Errorf("PriorityQueue: capacity must be positive: k=%d", k))
+	}
+	pq := newPriorityQueue(less, 1)
+	pq.capacity = k
+	return pq
+}
+
+func newPriorityQueue[T any](less func(a, b T) bool, shift uint) *priorityQueue[T] {
 	pq := new(priorityQueue[T])
 	pq.s = make([]T, 0)
 	pq.less = less
+	pq.shift = shift
 	return pq
 }
 
+// container/heapを使うとPush/Popでanyへの変換が入るので、自前で実装している
 type priorityQueue[T any] struct {
-	s    []T
-	less func(a, b T) bool
+	s        []T
+	less     func(a, b T) bool
+	shift    uint // 子の数が1<<shift個の木
+	capacity int  // 0なら上限なし
 }
 
-func (pq *priorityQueue[T]) Top() T      { return pq.s[0] }
-func (pq *priorityQueue[T]) Enqueue(x T) { heap.Push(pq, x) }
-func (pq *priorityQueue[T]) Dequeue() T  { return heap.Pop(pq).(T) }
+func (pq *priorityQueue[T]) Len() int { return len(pq.s) }
 
-func (pq priorityQueue[T]) Len() int           { return len(pq.s) }
-func (pq priorityQueue[T]) Swap(i, j int)      { pq.s[i], pq.s[j] = pq.s[j], pq.s[i] }
-func (pq priorityQueue[T]) Less(i, j int) bool { return pq.less(pq.s[i], pq.s[j]) }
-func (pq *priorityQueue[T]) Push(x any) {
-	pq.s = append(pq.s, x.(T))
+func (pq *priorityQueue[T]) Top() T {
+	pq.checkNotEmpty()
+	return pq.s[0]
 }
-func (pq *priorityQueue[T]) Pop() any {
-	n := len(pq.s)
-	res := (pq.s)[n-1]
-	pq.s = (pq.s)[:n-1]
+
+func (pq *priorityQueue[T]) TryTop() (T, bool) {
+	if len(pq.s) == 0 {
+		return *new(T), false
+	}
+	return pq.s[0], true
+}
+
+func (pq *priorityQueue[T]) Enqueue(x T) {
+	if pq.capacity > 0 && len(pq.s) == pq.capacity {
+		// 先頭より後ろに来る場合だけ、先頭と入れ替える
+		if pq.less(pq.s[0], x) {
+			pq.s[0] = x
+			pq.down(0)
+		}
+		return
+	}
+	pq.s = append(pq.s, x)
+	pq.up(len(pq.s) - 1)
+}
+
+func (pq *priorityQueue[T]) Dequeue() T {
+	pq.checkNotEmpty()
+	return pq.dequeue()
+}
+
+func (pq *priorityQueue[T]) TryDequeue() (T, bool) {
+	if len(pq.s) == 0 {
+		return *new(T), false
+	}
+	return pq.dequeue(), true
+}
+
+func (pq *priorityQueue[T]) dequeue() T {
+	res := pq.s[0]
+	n := len(pq.s) - 1
+	pq.s[0] = pq.s[n]
+	clear(pq.s[n:])
+	pq.s = pq.s[:n]
+	if n > 0 {
+		pq.down(0)
+	}
+	return res
+}
+
+func (pq *priorityQueue[T]) Init(s []T) {
+	clear(pq.s)
+	pq.s = append(pq.s[:0], s...)
+	for i := (len(pq.s) - 2) >> pq.shift; i >= 0; i-- {
+		pq.down(i)
+	}
+	for pq.capacity > 0 && len(pq.s) > pq.capacity {
+		pq.dequeue()
+	}
+}
+
+func (pq *priorityQueue[T]) Clear() {
+	clear(pq.s)
+	pq.s = pq.s[:0]
+}
+
+// 先頭から順に、取り出した要素の子を候補として別のヒープに入れていく
+func (pq *priorityQueue[T]) PeekN(k int) []T {
+	k = min(k, len(pq.s))
+	res := make([]T, 0, k)
+	if k <= 0 {
+		return res
+	}
+	cand := newPriorityQueue(func(i, j int) bool { return pq.less(pq.s[i], pq.s[j]) }, pq.shift)
+	cand.Enqueue(0)
+	for len(res) < k {
+		i := cand.dequeue()
+		res = append(res, pq.s[i])
+		for c := i<<pq.shift + 1; c < min(i<<pq.shift+1+1<<pq.shift, len(pq.s)); c++ {
+			cand.Enqueue(c)
+		}
+	}
+	return res
+}
+
+func (pq *priorityQueue[T]) DrainSorted() []T {
+	res := make([]T, 0, len(pq.s))
+	for len(pq.s) > 0 {
+		res = append(res, pq.dequeue())
+	}
 	return res
 }
+
+func (pq *priorityQueue[T]) up(i int) {
+	x := pq.s[i]
+	for i > 0 {
+		p := (i - 1) >> pq.shift
+		if !pq.less(x, pq.s[p]) {
+			break
+		}
+		pq.s[i] = pq.s[p]
+		i = p
+	}
+	pq.s[i] = x
+}
+
+func (pq *priorityQueue[T]) down(i int) {
+	n := len(pq.s)
+	x := pq.s[i]
+	for {
+		c := i<<pq.shift + 1
+		if c >= n {
+			break
+		}
+		// 子のうち最も優先度が高いもの
+		best := c
+		for j := c + 1; j < min(c+1<<pq.shift, n); j++ {
+			if pq.less(pq.s[j], pq.s[best]) {
+				best = j
+			}
+		}
+		if !pq.less(pq.s[best], x) {
+			break
+		}
+		pq.s[i] = pq.s[best]
+		i = best
+	}
+	pq.s[i] = x
+}
+
+func (pq *priorityQueue[T]) checkNotEmpty() {
+	if len(pq.s) == 0 {
+		panic(fmt.Errorf("PriorityQueue: queue is empty"))
+	}
+}
@@ -0,0 +1,92 @@
+package priorityqueue
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// 非負整数のキーを持つ要素を、キーの昇順に取り出すヒープ
+// 取り出したキーより小さいキーは入れられない (Dijkstraなどキーが単調に増える場合に使う)
+// 各操作はならしO(log C) (Cはキーの最大値)
+type RadixHeap[V any] interface {
+	Len() int
+	// キーが最小の要素
+	Top() (int64, V)
+	// 最後に取り出したキーより小さいキーを入れるとpanic
+	Enqueue(key int64, v V)
+	Dequeue() (int64, V)
+}
+
+func NewRadixHeap[V any]() RadixHeap[V] {
+	return &radixHeap[V]{}
+}
+
+// キーをlastとの排他的論理和の最上位ビットでバケットに分けて持つ
+// バケット0にはキーがlastに等しい要素だけが入る
+type radixHeap[V any] struct {
+	buckets [65][]radixItem[V]
+	last    int64
+	len     int
+}
+
+type radixItem[V any] struct {
+	key int64
+	v   V
+}
+
+func (h *radixHeap[V]) Len() int {
+	return h.len
+}
+
+func (h *radixHeap[V]) Top() (int64, V) {
+	h.pull()
+	it := h.buckets[0][len(h.buckets[0])-1]
+	return it.key, it.v
+}
+
+func (h *radixHeap[V]) Enqueue(key int64, v V) {
+	if key < h.last {
+		panic(fmt.Errorf("RadixHeap: key must not be less than the last dequeued key: key=%d, last=%d", key, h.last))
+	}
+	b := h.bucket(key)
+	h.buckets[b] = append(h.buckets[b], radixItem[V]{key, v})
+	h.len++
+}
+
+func (h *radixHeap[V]) Dequeue() (int64, V) {
+	h.pull()
+	n := len(h.buckets[0]) - 1
+	it := h.buckets[0][n]
+	h.buckets[0][n] = radixItem[V]{}
+	h.buckets[0] = h.buckets[0][:n]
+	h.len--
+	return it.key, it.v
+}
+
+func (h *radixHeap[V]) bucket(key int64) int {
+	return bits.Len64(uint64(key ^ h.last))
+}
+
+// バケット0が空なら、空でない最初のバケットの最小のキーをlastにして配り直す
+func (h *radixHeap[V]) pull() {
+	if h.len == 0 {
+		panic(fmt.Errorf("RadixHeap: heap is empty"))
+	}
+	if len(h.buckets[0]) > 0 {
+		return
+	}
+	i := 1
+	for len(h.buckets[i]) == 0 {
+		i++
+	}
+	h.last = h.buckets[i][0].key
+	for _, it := range h.buckets[i][1:] {
+		h.last = min(h.last, it.key)
+	}
+	for _, it := range h.buckets[i] {
+		b := h.bucket(it.key)
+		h.buckets[b] = append(h.buckets[b], it)
+	}
+	clear(h.buckets[i])
+	h.buckets[i] = h.buckets[i][:0]
+}
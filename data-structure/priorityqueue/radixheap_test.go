@@ -0,0 +1,84 @@
+package priorityqueue
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+const (
+	benchN = 1 << 16
+	benchM = benchN * 8
+)
+
+type benchEdge struct {
+	to int
+	w  int64
+}
+
+func genBenchGraph(n, m int) [][]benchEdge {
+	rnd := rand.New(rand.NewSource(1))
+	g := make([][]benchEdge, n)
+	for range m {
+		u, v := rnd.Intn(n), rnd.Intn(n)
+		g[u] = append(g[u], benchEdge{v, rnd.Int63n(1 << 30)})
+	}
+	return g
+}
+
+func newDist(n int) []int64 {
+	dist := make([]int64, n)
+	for i := range dist {
+		dist[i] = math.MaxInt64
+	}
+	dist[0] = 0
+	return dist
+}
+
+func BenchmarkDijkstra_RadixHeap(b *testing.B) {
+	g := genBenchGraph(benchN, benchM)
+	b.ResetTimer()
+	for range b.N {
+		dist := newDist(benchN)
+		h := NewRadixHeap[int]()
+		h.Enqueue(0, 0)
+		for h.Len() > 0 {
+			d, u := h.Dequeue()
+			if dist[u] < d {
+				continue
+			}
+			for _, e := range g[u] {
+				if nd := d + e.w; nd < dist[e.to] {
+					dist[e.to] = nd
+					h.Enqueue(nd, e.to)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkDijkstra_PriorityQueue(b *testing.B) {
+	type item struct {
+		d int64
+		u int
+	}
+	g := genBenchGraph(benchN, benchM)
+	b.ResetTimer()
+	for range b.N {
+		dist := newDist(benchN)
+		pq := NewPriorityQueueFunc(func(a, b item) bool { return a.d < b.d })
+		pq.Enqueue(item{0, 0})
+		for pq.Len() > 0 {
+			it := pq.Dequeue()
+			if dist[it.u] < it.d {
+				continue
+			}
+			for _, e := range g[it.u] {
+				if nd := it.d + e.w; nd < dist[e.to] {
+					dist[e.to] = nd
+					pq.Enqueue(item{nd, e.to})
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package priorityqueue
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// 任意の要素を削除できる優先度付きキュー
+// 削除する要素を別のヒープに入れておき、先頭に来たときにまとめて取り除く
+type RemovablePriorityQueue[T any] interface {
+	PriorityQueue[T]
+	// xを1つ取り除く xがキューに入っていない場合の動作は未定義
+	Erase(x T)
+}
+
+// 昇順
+func NewRemovablePriorityQueue[T cmp.Ordered]() RemovablePriorityQueue[T] {
+	return NewRemovablePriorityQueueFunc(cmp.Less[T])
+}
+
+// less(a, b)とless(b, a)がどちらもfalseのとき、aとbは等しいとみなす
+func NewRemovablePriorityQueueFunc[T any](less func(a, b T) bool) RemovablePriorityQueue[T] {
+	return &removablePriorityQueue[T]{
+		q:    NewPriorityQueueFunc(less),
+		del:  NewPriorityQueueFunc(less),
+		less: less,
+	}
+}
+
+type removablePriorityQueue[T any] struct {
+	q, del PriorityQueue[T]
+	less   func(a, b T) bool
+}
+
+func (pq *removablePriorityQueue[T]) Len() int {
+	return pq.q.Len() - pq.del.Len()
+}
+
+func (pq *removablePriorityQueue[T]) Top() T {
+	pq.checkNotEmpty()
+	pq.flush()
+	return pq.q.Top()
+}
+
+func (pq *removablePriorityQueue[T]) TryTop() (T, bool) {
+	if pq.Len() == 0 {
+		return *new(T), false
+	}
+	return pq.Top(), true
+}
+
+func (pq *removablePriorityQueue[T]) Enqueue(x T) {
+	pq.q.Enqueue(x)
+}
+
+func (pq *removablePriorityQueue[T]) Dequeue() T {
+	pq.checkNotEmpty()
+	pq.flush()
+	return pq.q.Dequeue()
+}
+
+func (pq *removablePriorityQueue[T]) TryDequeue() (T, bool) {
+	if pq.Len() == 0 {
+		return *new(T), false
+	}
+	return pq.Dequeue(), true
+}
+
+func (pq *removablePriorityQueue[T]) Erase(x T) {
+	pq.del.Enqueue(x)
+}
+
+// 削除待ちの要素が先頭にある間、取り除く
+func (pq *removablePriorityQueue[T]) flush() {
+	for pq.del.Len() > 0 {
+		a, b := pq.q.Top(), pq.del.Top()
+		if pq.less(a, b) || pq.less(b, a) {
+			return
+		}
+		pq.q.Dequeue()
+		pq.del.Dequeue()
+	}
+}
+
+func (pq *removablePriorityQueue[T]) checkNotEmpty() {
+	if pq.Len() == 0 {
+		panic(fmt.Errorf("RemovablePriorityQueue: queue is empty"))
+	}
+}
@@ -0,0 +1,107 @@
+package priorityqueue
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// skew heap 各操作はならしO(log n)
+func NewSkewHeap[T cmp.Ordered]() MeldablePriorityQueue[T] {
+	return NewSkewHeapFunc(cmp.Less[T])
+}
+
+func NewSkewHeapFunc[T any](less func(a, b T) bool) MeldablePriorityQueue[T] {
+	return &skewHeap[T]{less: less}
+}
+
+type skewHeap[T any] struct {
+	root *skewNode[T]
+	len  int
+	less func(a, b T) bool
+}
+
+type skewNode[T any] struct {
+	x    T
+	l, r *skewNode[T]
+}
+
+func (h *skewHeap[T]) Len() int {
+	return h.len
+}
+
+func (h *skewHeap[T]) Top() T {
+	h.checkNotEmpty()
+	return h.root.x
+}
+
+func (h *skewHeap[T]) TryTop() (T, bool) {
+	if h.root == nil {
+		return *new(T), false
+	}
+	return h.root.x, true
+}
+
+func (h *skewHeap[T]) Enqueue(x T) {
+	h.root = h.meld(h.root, &skewNode[T]{x: x})
+	h.len++
+}
+
+func (h *skewHeap[T]) Dequeue() T {
+	h.checkNotEmpty()
+	res := h.root.x
+	h.root = h.meld(h.root.l, h.root.r)
+	h.len--
+	return res
+}
+
+func (h *skewHeap[T]) TryDequeue() (T, bool) {
+	if h.root == nil {
+		return *new(T), false
+	}
+	return h.Dequeue(), true
+}
+
+func (h *skewHeap[T]) Meld(other MeldablePriorityQueue[T]) {
+	o := other.(*skewHeap[T])
+	if o == h {
+		return
+	}
+	h.root = h.meld(h.root, o.root)
+	h.len += o.len
+	o.root, o.len = nil, 0
+}
+
+// 右の子の列が長くなることがあるので、再帰を使わずに上から併合する
+// 通ったノードは左右の子を入れ替える
+func (h *skewHeap[T]) meld(a, b *skewNode[T]) *skewNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if h.less(b.x, a.x) {
+		a, b = b, a
+	}
+	root := a
+	for {
+		// a.rとbを併合したものをa.lにする
+		r := a.r
+		a.r = a.l
+		if r == nil {
+			a.l = b
+			return root
+		}
+		if h.less(b.x, r.x) {
+			r, b = b, r
+		}
+		a.l = r
+		a = r
+	}
+}
+
+func (h *skewHeap[T]) checkNotEmpty() {
+	if h.root == nil {
+		panic(fmt.Errorf("SkewHeap: queue is empty"))
+	}
+}
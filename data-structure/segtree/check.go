@@ -0,0 +1,22 @@
+//go:build !nocheck
+
+package segtree
+
+import "fmt"
+
+// -tags nocheck でビルドすると、SegmentTreeの範囲チェックを省略する
+
+func (sg *segmentTree[T]) checkInRange(i int) {
+	if i < 0 || sg.n <= i {
+		panic(fmt.Errorf("SegmentTree: index out of range: n=%d, i=%d", sg.n, i))
+	}
+}
+
+func (sg *segmentTree[T]) checkInRangeLR(l, r int) {
+	if l < 0 || sg.n < r {
+		panic(fmt.Errorf("SegmentTree: index out of range: n=%d, l=%d, r=%d", sg.n, l, r))
+	}
+	if r < l {
+		panic(fmt.Errorf("SegmentTree: invalid range: l=%d > r=%d", l, r))
+	}
+}
@@ -1,12 +1,27 @@
 package segtree
 
-import "fmt"
+import (
+	"fmt"
+	"iter"
+)
 
 type DynamicSegmentTree[T any] interface {
 	Set(i int, val T)
 	Get(i int) T
 	Product(l, r int) T
 	ProductAll() T
+
+	// s[i]をl+i番目の要素として全体を作り直す 残りの位置はe()になる
+	Assign(s []T)
+	// 全要素をe()に戻す 確保済みのノードは次回以降に使い回す
+	Reset()
+	// [l, r)の要素をスライスにして返す
+	Slice() []T
+
+	// 値を持っているノードの数 (Setされた添字の数)
+	Count() int
+	// Setされた添字と値の組を添字の昇順に列挙する
+	All() iter.Seq2[int, T]
 }
 
 func NewDynamicSegmentTree[T any](l, r int, e func() T, op func(a, b T) T) DynamicSegmentTree[T] {
@@ -19,11 +34,10 @@ func NewDynamicSegmentTree[T any](l, r int, e func() T, op func(a, b T) T) Dynam
 	}
 }
 
+// O(n)で構築する
 func NewDynamicSegmentTreeWith[T any](s []T, e func() T, op func(a, b T) T) DynamicSegmentTree[T] {
 	sg := NewDynamicSegmentTree(0, len(s), e, op)
-	for i, val := range s {
-		sg.Set(i, val)
-	}
+	sg.Assign(s)
 	return sg
 }
 
@@ -34,6 +48,8 @@ func NewDynamicSegmentTreeWith[T any](s []T, e func() T, op func(a, b T) T) Dyna
 type dynamicSegmentTree[T any] struct {
 	l, r int
 	root *node[T]
+	cnt  int        // ノード数
+	pool []*node[T] // Resetで回収したノード
 	e    func() T
 	op   func(a, b T) T
 }
@@ -41,7 +57,7 @@ type dynamicSegmentTree[T any] struct {
 func (sg *dynamicSegmentTree[T]) Set(i int, val T) {
 	sg.checkInRange(i)
 	if sg.root == nil {
-		sg.root = newNode(i, val)
+		sg.root = sg.newNode(i, val)
 		return
 	}
 
@@ -68,7 +84,7 @@ func (sg *dynamicSegmentTree[T]) Set(i int, val T) {
 			l = m
 		}
 		if now == nil {
-			now = newNode(i, val)
+			now = sg.newNode(i, val)
 			now.p = p
 			if now.i < p.i {
 				p.l = now
@@ -123,7 +139,98 @@ func (sg *dynamicSegmentTree[T]) product(n *node[T], argL, argR, l, r int) T {
 }
 
 func (sg *dynamicSegmentTree[T]) ProductAll() T {
-	return sg.root.subVal
+	return sg.subtreeVal(sg.root)
+}
+
+func (sg *dynamicSegmentTree[T]) Assign(s []T) {
+	if len(s) > sg.r-sg.l {
+		panic(fmt.Errorf("DynamicSegmentTree: slice too long: l=%d, r=%d, len(s)=%d", sg.l, sg.r, len(s)))
+	}
+	sg.Reset()
+	sg.root = sg.build(s, nil, sg.l, sg.r, sg.l, sg.l+len(s))
+}
+
+// 区間[l, r)を担当する部分木を、添字[lo, hi)の要素が全部埋まった状態で作る
+// 左右の子の担当区間に収まるよう、左半分に要素があれば左半分の最大の添字を、なければ最小の添字を自身に持たせる
+func (sg *dynamicSegmentTree[T]) build(s []T, p *node[T], l, r, lo, hi int) *node[T] {
+	if lo >= hi {
+		return nil
+	}
+	m := (l + r) / 2
+	i := lo
+	if lo < m {
+		i = min(m, hi) - 1
+	}
+	n := sg.newNode(i, s[i-sg.l])
+	n.p = p
+	if lo < m {
+		n.l = sg.build(s, n, l, m, lo, i)
+		n.r = sg.build(s, n, m, r, m, hi)
+	} else {
+		n.r = sg.build(s, n, m, r, i+1, hi)
+	}
+	sg.update(n)
+	return n
+}
+
+func (sg *dynamicSegmentTree[T]) Reset() {
+	if sg.root == nil {
+		return
+	}
+	stack := []*node[T]{sg.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n.l != nil {
+			stack = append(stack, n.l)
+		}
+		if n.r != nil {
+			stack = append(stack, n.r)
+		}
+		*n = node[T]{}
+		sg.pool = append(sg.pool, n)
+	}
+	sg.root = nil
+	sg.cnt = 0
+}
+
+func (sg *dynamicSegmentTree[T]) Slice() []T {
+	res := make([]T, sg.r-sg.l)
+	for i := range res {
+		res[i] = sg.e()
+	}
+	for i, val := range sg.All() {
+		res[i-sg.l] = val
+	}
+	return res
+}
+
+func (sg *dynamicSegmentTree[T]) Count() int {
+	return sg.cnt
+}
+
+func (sg *dynamicSegmentTree[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		var dfs func(n *node[T]) bool
+		dfs = func(n *node[T]) bool {
+			if n == nil {
+				return true
+			}
+			return dfs(n.l) && yield(n.i, n.val) && dfs(n.r)
+		}
+		dfs(sg.root)
+	}
+}
+
+func (sg *dynamicSegmentTree[T]) newNode(i int, val T) *node[T] {
+	sg.cnt++
+	if k := len(sg.pool); k > 0 {
+		n := sg.pool[k-1]
+		sg.pool = sg.pool[:k-1]
+		n.i, n.val, n.subVal = i, val, val
+		return n
+	}
+	return newNode(i, val)
 }
 
 func (sg *dynamicSegmentTree[T]) update(n *node[T]) {
@@ -0,0 +1,6 @@
+//go:build nocheck
+
+package segtree
+
+func (sg *segmentTree[T]) checkInRange(i int)      {}
+func (sg *segmentTree[T]) checkInRangeLR(l, r int) {}
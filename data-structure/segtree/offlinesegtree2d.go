@@ -0,0 +1,158 @@
+package segtree
+
+import (
+	"fmt"
+	"slices"
+)
+
+// 事前に与えた点(xs[k], ys[k])のみ値を持てる2次元セグメントツリー
+// 座標は大きくてもよい opは可換である必要がある
+type OfflineSegmentTree2D[T any] interface {
+	Set(x, y int, val T)
+	Get(x, y int) T
+	// [x1, x2)×[y1, y2)に含まれる点の積
+	Product(x1, y1, x2, y2 int) T
+	ProductAll() T
+}
+
+// 空間計算量 O(n log n)
+func NewOfflineSegmentTree2D[T any](xs, ys []int, e func() T, op func(a, b T) T) OfflineSegmentTree2D[T] {
+	if len(xs) != len(ys) {
+		panic(fmt.Errorf("OfflineSegmentTree2D: len(xs) != len(ys): len(xs)=%d, len(ys)=%d", len(xs), len(ys)))
+	}
+	sortedX := slices.Clone(xs)
+	slices.Sort(sortedX)
+	sortedX = slices.Compact(sortedX)
+	n := len(sortedX)
+
+	nodeY := make([][]int, n*2)
+	for k := range xs {
+		i, _ := slices.BinarySearch(sortedX, xs[k])
+		nodeY[n+i] = append(nodeY[n+i], ys[k])
+	}
+	for i := n; i < n*2; i++ {
+		slices.Sort(nodeY[i])
+		nodeY[i] = slices.Compact(nodeY[i])
+	}
+	for i := n - 1; i >= 1; i-- {
+		nodeY[i] = mergeUnique(nodeY[i*2], nodeY[i*2+1])
+	}
+
+	inner := make([]SegmentTree[T], n*2)
+	for i := 1; i < n*2; i++ {
+		inner[i] = NewSegmentTree(len(nodeY[i]), e, op)
+	}
+	return &offlineSegmentTree2D[T]{
+		n:     n,
+		xs:    sortedX,
+		nodeY: nodeY,
+		inner: inner,
+		e:     e,
+		op:    op,
+	}
+}
+
+// x方向のセグメントツリーの各ノードに、担当する点のy座標のソート済みリストと
+// そのリスト上のセグメントツリーを持たせる
+type offlineSegmentTree2D[T any] struct {
+	n     int
+	xs    []int   // 座標圧縮したx
+	nodeY [][]int // 各ノードが担当する点のy座標 (ソート済み)
+	inner []SegmentTree[T]
+	e     func() T
+	op    func(a, b T) T
+}
+
+func (sg *offlineSegmentTree2D[T]) Set(x, y int, val T) {
+	i, ok := slices.BinarySearch(sg.xs, x)
+	if !ok {
+		sg.panicNotFound(x, y)
+	}
+	now := sg.n + i
+	j, ok := slices.BinarySearch(sg.nodeY[now], y)
+	if !ok {
+		sg.panicNotFound(x, y)
+	}
+	sg.inner[now].Set(j, val)
+	for now > 1 {
+		now /= 2
+		j, _ := slices.BinarySearch(sg.nodeY[now], y)
+		sg.inner[now].Set(j, sg.op(sg.get(now*2, y), sg.get(now*2+1, y)))
+	}
+}
+
+func (sg *offlineSegmentTree2D[T]) Get(x, y int) T {
+	i, ok := slices.BinarySearch(sg.xs, x)
+	if !ok {
+		sg.panicNotFound(x, y)
+	}
+	if _, ok := slices.BinarySearch(sg.nodeY[sg.n+i], y); !ok {
+		sg.panicNotFound(x, y)
+	}
+	return sg.get(sg.n+i, y)
+}
+
+func (sg *offlineSegmentTree2D[T]) Product(x1, y1, x2, y2 int) T {
+	if x2 < x1 || y2 < y1 {
+		panic(fmt.Errorf("OfflineSegmentTree2D: invalid range: x1=%d, y1=%d, x2=%d, y2=%d", x1, y1, x2, y2))
+	}
+	l, _ := slices.BinarySearch(sg.xs, x1)
+	r, _ := slices.BinarySearch(sg.xs, x2)
+	res := sg.e()
+	for l, r = l+sg.n, r+sg.n; l < r; l, r = l/2, r/2 {
+		if l%2 == 1 {
+			res = sg.op(res, sg.product(l, y1, y2))
+			l++
+		}
+		if r%2 == 1 {
+			r--
+			res = sg.op(res, sg.product(r, y1, y2))
+		}
+	}
+	return res
+}
+
+// 根(ノード1)が全ての点を持っている
+func (sg *offlineSegmentTree2D[T]) ProductAll() T {
+	if sg.n == 0 {
+		return sg.e()
+	}
+	return sg.inner[1].ProductAll()
+}
+
+// ノードkが持つ点yの値 yを持っていなければe()
+func (sg *offlineSegmentTree2D[T]) get(k, y int) T {
+	if j, ok := slices.BinarySearch(sg.nodeY[k], y); ok {
+		return sg.inner[k].Get(j)
+	}
+	return sg.e()
+}
+
+func (sg *offlineSegmentTree2D[T]) product(k, y1, y2 int) T {
+	a, _ := slices.BinarySearch(sg.nodeY[k], y1)
+	b, _ := slices.BinarySearch(sg.nodeY[k], y2)
+	return sg.inner[k].Product(a, b)
+}
+
+func (sg *offlineSegmentTree2D[T]) panicNotFound(x, y int) {
+	panic(fmt.Errorf("OfflineSegmentTree2D: point not registered: x=%d, y=%d", x, y))
+}
+
+// ソート済みのa, bをマージして重複を除く
+func mergeUnique(a, b []int) []int {
+	res := make([]int, 0, len(a)+len(b))
+	for i, j := 0, 0; i < len(a) || j < len(b); {
+		var v int
+		if j == len(b) || (i < len(a) && a[i] <= b[j]) {
+			v = a[i]
+			i++
+		} else {
+			v = b[j]
+			j++
+		}
+		if len(res) == 0 || res[len(res)-1] != v {
+			res = append(res, v)
+		}
+	}
+	return res
+}
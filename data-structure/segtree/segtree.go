@@ -1,11 +1,23 @@
 package segtree
 
+import (
+	"fmt"
+	"slices"
+)
+
 type SegmentTree[T any] interface {
 	Len() int
 	Set(i int, val T)
 	Get(i int) T
 	Product(l, r int) T
 	ProductAll() T
+
+	// s[i]をi番目の要素として全体を作り直す len(s)以降はe()で埋める
+	Assign(s []T)
+	// 全要素をe()に戻す
+	Reset()
+	// 現在の要素をスライスにして返す
+	Slice() []T
 }
 
 func NewSegmentTree[T any](n int, e func() T, op func(a, b T) T) SegmentTree[T] {
@@ -57,6 +69,7 @@ func (sg *segmentTree[T]) Len() int {
 }
 
 func (sg *segmentTree[T]) Set(i int, val T) {
+	sg.checkInRange(i)
 	now := sg.n + i
 	sg.data[now] = val
 	for now > 1 {
@@ -66,10 +79,12 @@ func (sg *segmentTree[T]) Set(i int, val T) {
 }
 
 func (sg *segmentTree[T]) Get(i int) T {
+	sg.checkInRange(i)
 	return sg.data[sg.n+i]
 }
 
 func (sg *segmentTree[T]) Product(l, r int) T {
+	sg.checkInRangeLR(l, r)
 	l += sg.n
 	r += sg.n
 	valL, valR := sg.e(), sg.e()
@@ -92,6 +107,29 @@ func (sg *segmentTree[T]) ProductAll() T {
 	return sg.Product(0, sg.n)
 }
 
+func (sg *segmentTree[T]) Assign(s []T) {
+	if len(s) > sg.n {
+		panic(fmt.Errorf("SegmentTree: slice too long: n=%d, len(s)=%d", sg.n, len(s)))
+	}
+	copy(sg.data[sg.n:], s)
+	for i := sg.n + len(s); i < len(sg.data); i++ {
+		sg.data[i] = sg.e()
+	}
+	for i := sg.n - 1; i >= 1; i-- {
+		sg.update(i)
+	}
+}
+
+func (sg *segmentTree[T]) Reset() {
+	for i := range sg.data {
+		sg.data[i] = sg.e()
+	}
+}
+
+func (sg *segmentTree[T]) Slice() []T {
+	return slices.Clone(sg.data[sg.n:])
+}
+
 func (sg *segmentTree[T]) update(now int) {
 	child1, child2 := now*2, now*2+1
 	sg.data[now] = sg.op(sg.data[child1], sg.data[child2])
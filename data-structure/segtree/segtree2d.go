@@ -0,0 +1,87 @@
+package segtree
+
+import "fmt"
+
+// opは可換である必要がある
+type SegmentTree2D[T any] interface {
+	Len() (h, w int)
+	Set(i, j int, val T)
+	Get(i, j int) T
+	// [i1, i2)×[j1, j2)の積
+	Product(i1, j1, i2, j2 int) T
+	ProductAll() T
+}
+
+// h×wの全マスを持つ 小さいグリッド向け
+func NewSegmentTree2D[T any](h, w int, e func() T, op func(a, b T) T) SegmentTree2D[T] {
+	rows := make([]SegmentTree[T], h*2)
+	for i := range rows {
+		rows[i] = NewSegmentTree(w, e, op)
+	}
+	return &segmentTree2D[T]{
+		h:    h,
+		w:    w,
+		rows: rows,
+		e:    e,
+		op:   op,
+	}
+}
+
+// 行方向のセグメントツリーの各ノードに、列方向のセグメントツリーを持たせる
+type segmentTree2D[T any] struct {
+	h, w int
+	rows []SegmentTree[T]
+	e    func() T
+	op   func(a, b T) T
+}
+
+func (sg *segmentTree2D[T]) Len() (h, w int) {
+	return sg.h, sg.w
+}
+
+func (sg *segmentTree2D[T]) Set(i, j int, val T) {
+	sg.checkInRange(i, j)
+	now := sg.h + i
+	sg.rows[now].Set(j, val)
+	for now > 1 {
+		now /= 2
+		sg.rows[now].Set(j, sg.op(sg.rows[now*2].Get(j), sg.rows[now*2+1].Get(j)))
+	}
+}
+
+func (sg *segmentTree2D[T]) Get(i, j int) T {
+	sg.checkInRange(i, j)
+	return sg.rows[sg.h+i].Get(j)
+}
+
+func (sg *segmentTree2D[T]) Product(i1, j1, i2, j2 int) T {
+	sg.checkInRangeLR(i1, j1, i2, j2)
+	res := sg.e()
+	for l, r := i1+sg.h, i2+sg.h; l < r; l, r = l/2, r/2 {
+		if l%2 == 1 {
+			res = sg.op(res, sg.rows[l].Product(j1, j2))
+			l++
+		}
+		if r%2 == 1 {
+			r--
+			res = sg.op(res, sg.rows[r].Product(j1, j2))
+		}
+	}
+	return res
+}
+
+func (sg *segmentTree2D[T]) ProductAll() T {
+	return sg.Product(0, 0, sg.h, sg.w)
+}
+
+func (sg *segmentTree2D[T]) checkInRange(i, j int) {
+	if i < 0 || sg.h <= i || j < 0 || sg.w <= j {
+		panic(fmt.Errorf("SegmentTree2D: index out of range: h=%d, w=%d, i=%d, j=%d", sg.h, sg.w, i, j))
+	}
+}
+
+func (sg *segmentTree2D[T]) checkInRangeLR(i1, j1, i2, j2 int) {
+	if i1 < 0 || i2 < i1 || sg.h < i2 || j1 < 0 || j2 < j1 || sg.w < j2 {
+		panic(fmt.Errorf("SegmentTree2D: index out of range: h=%d, w=%d, i1=%d, j1=%d, i2=%d, j2=%d", sg.h, sg.w, i1, j1, i2, j2))
+	}
+}
@@ -0,0 +1,309 @@
+package segtree
+
+import (
+	"fmt"
+	"math"
+)
+
+// 区間chmin, 区間chmax, 区間加算と、区間和・区間最小値・区間最大値の取得ができる
+// 各操作はならしO(log^2 n)
+type SegmentTreeBeats interface {
+	Len() int
+	Set(i, x int)
+	Get(i int) int
+
+	// [l, r)の各要素をmin(a[i], x)にする
+	Chmin(l, r, x int)
+	// [l, r)の各要素をmax(a[i], x)にする
+	Chmax(l, r, x int)
+	// [l, r)の各要素にxを足す
+	Add(l, r, x int)
+
+	// 空区間なら0
+	Sum(l, r int) int
+	// 空区間ならmath.MaxInt
+	Min(l, r int) int
+	// 空区間ならmath.MinInt
+	Max(l, r int) int
+}
+
+func NewSegmentTreeBeats(n int) SegmentTreeBeats {
+	return NewSegmentTreeBeatsWith(make([]int, n))
+}
+
+func NewSegmentTreeBeatsWith(s []int) SegmentTreeBeats {
+	n := len(s)
+	size := max(n, 1) * 4
+	sg := &segmentTreeBeats{
+		n:    n,
+		max1: make([]int, size),
+		max2: make([]int, size),
+		maxc: make([]int, size),
+		min1: make([]int, size),
+		min2: make([]int, size),
+		minc: make([]int, size),
+		sum:  make([]int, size),
+		lazy: make([]int, size),
+	}
+	if n > 0 {
+		sg.build(s, 1, 0, n)
+	}
+	return sg
+}
+
+// Ji driver segment tree
+// 各ノードに最大値・2番目の最大値・最大値の個数(最小値側も同様)を持たせ、
+// chminで2番目の最大値を下回らない限りそのノードで処理を打ち切る
+// 参考にさせていただいた記事:
+// https://smijake3.hatenablog.com/entry/2019/04/28/021457
+// https://codeforces.com/blog/entry/57319
+type segmentTreeBeats struct {
+	n          int
+	max1, max2 []int // 最大値, 2番目の最大値 (存在しなければmath.MinInt)
+	maxc       []int // 最大値の個数
+	min1, min2 []int // 最小値, 2番目の最小値 (存在しなければmath.MaxInt)
+	minc       []int // 最小値の個数
+	sum        []int
+	lazy       []int // 子に伝播していない加算
+}
+
+func (sg *segmentTreeBeats) Len() int {
+	return sg.n
+}
+
+func (sg *segmentTreeBeats) Set(i, x int) {
+	sg.checkInRange(i)
+	sg.set(1, 0, sg.n, i, x)
+}
+
+func (sg *segmentTreeBeats) Get(i int) int {
+	sg.checkInRange(i)
+	return sg.Sum(i, i+1)
+}
+
+func (sg *segmentTreeBeats) Chmin(l, r, x int) {
+	sg.checkInRangeLR(l, r)
+	if l < r {
+		sg.chmin(1, 0, sg.n, l, r, x)
+	}
+}
+
+func (sg *segmentTreeBeats) Chmax(l, r, x int) {
+	sg.checkInRangeLR(l, r)
+	if l < r {
+		sg.chmax(1, 0, sg.n, l, r, x)
+	}
+}
+
+func (sg *segmentTreeBeats) Add(l, r, x int) {
+	sg.checkInRangeLR(l, r)
+	if l < r {
+		sg.add(1, 0, sg.n, l, r, x)
+	}
+}
+
+func (sg *segmentTreeBeats) Sum(l, r int) int {
+	sg.checkInRangeLR(l, r)
+	if l == r {
+		return 0
+	}
+	return sg.query(1, 0, sg.n, l, r, 0, func(k int) int { return sg.sum[k] }, func(a, b int) int { return a + b })
+}
+
+func (sg *segmentTreeBeats) Min(l, r int) int {
+	sg.checkInRangeLR(l, r)
+	if l == r {
+		return math.MaxInt
+	}
+	return sg.query(1, 0, sg.n, l, r, math.MaxInt, func(k int) int { return sg.min1[k] }, func(a, b int) int { return min(a, b) })
+}
+
+func (sg *segmentTreeBeats) Max(l, r int) int {
+	sg.checkInRangeLR(l, r)
+	if l == r {
+		return math.MinInt
+	}
+	return sg.query(1, 0, sg.n, l, r, math.MinInt, func(k int) int { return sg.max1[k] }, func(a, b int) int { return max(a, b) })
+}
+
+func (sg *segmentTreeBeats) build(s []int, k, l, r int) {
+	if r-l == 1 {
+		sg.setLeaf(k, s[l])
+		return
+	}
+	m := (l + r) / 2
+	sg.build(s, k*2, l, m)
+	sg.build(s, k*2+1, m, r)
+	sg.update(k)
+}
+
+func (sg *segmentTreeBeats) set(k, l, r, i, x int) {
+	if r-l == 1 {
+		sg.setLeaf(k, x)
+		return
+	}
+	sg.push(k, l, r)
+	if m := (l + r) / 2; i < m {
+		sg.set(k*2, l, m, i, x)
+	} else {
+		sg.set(k*2+1, m, r, i, x)
+	}
+	sg.update(k)
+}
+
+func (sg *segmentTreeBeats) chmin(k, l, r, argL, argR, x int) {
+	if argR <= l || r <= argL || sg.max1[k] <= x {
+		return
+	}
+	if argL <= l && r <= argR && sg.max2[k] < x {
+		sg.chminNode(k, x)
+		return
+	}
+	sg.push(k, l, r)
+	m := (l + r) / 2
+	sg.chmin(k*2, l, m, argL, argR, x)
+	sg.chmin(k*2+1, m, r, argL, argR, x)
+	sg.update(k)
+}
+
+func (sg *segmentTreeBeats) chmax(k, l, r, argL, argR, x int) {
+	if argR <= l || r <= argL || x <= sg.min1[k] {
+		return
+	}
+	if argL <= l && r <= argR && x < sg.min2[k] {
+		sg.chmaxNode(k, x)
+		return
+	}
+	sg.push(k, l, r)
+	m := (l + r) / 2
+	sg.chmax(k*2, l, m, argL, argR, x)
+	sg.chmax(k*2+1, m, r, argL, argR, x)
+	sg.update(k)
+}
+
+func (sg *segmentTreeBeats) add(k, l, r, argL, argR, x int) {
+	if argR <= l || r <= argL {
+		return
+	}
+	if argL <= l && r <= argR {
+		sg.addNode(k, r-l, x)
+		return
+	}
+	sg.push(k, l, r)
+	m := (l + r) / 2
+	sg.add(k*2, l, m, argL, argR, x)
+	sg.add(k*2+1, m, r, argL, argR, x)
+	sg.update(k)
+}
+
+func (sg *segmentTreeBeats) query(k, l, r, argL, argR, e int, val func(k int) int, op func(a, b int) int) int {
+	if argR <= l || r <= argL {
+		return e
+	}
+	if argL <= l && r <= argR {
+		return val(k)
+	}
+	sg.push(k, l, r)
+	m := (l + r) / 2
+	return op(sg.query(k*2, l, m, argL, argR, e, val, op), sg.query(k*2+1, m, r, argL, argR, e, val, op))
+}
+
+func (sg *segmentTreeBeats) setLeaf(k, x int) {
+	sg.max1[k], sg.max2[k], sg.maxc[k] = x, math.MinInt, 1
+	sg.min1[k], sg.min2[k], sg.minc[k] = x, math.MaxInt, 1
+	sg.sum[k] = x
+	sg.lazy[k] = 0
+}
+
+// ノードkの最大値をxに下げる max2[k] < x < max1[k]が前提
+func (sg *segmentTreeBeats) chminNode(k, x int) {
+	sg.sum[k] += (x - sg.max1[k]) * sg.maxc[k]
+	if sg.max1[k] == sg.min1[k] {
+		sg.min1[k] = x
+	} else if sg.max1[k] == sg.min2[k] {
+		sg.min2[k] = x
+	}
+	sg.max1[k] = x
+}
+
+// ノードkの最小値をxに上げる min1[k] < x < min2[k]が前提
+func (sg *segmentTreeBeats) chmaxNode(k, x int) {
+	sg.sum[k] += (x - sg.min1[k]) * sg.minc[k]
+	if sg.min1[k] == sg.max1[k] {
+		sg.max1[k] = x
+	} else if sg.min1[k] == sg.max2[k] {
+		sg.max2[k] = x
+	}
+	sg.min1[k] = x
+}
+
+func (sg *segmentTreeBeats) addNode(k, length, x int) {
+	sg.max1[k] += x
+	if sg.max2[k] != math.MinInt {
+		sg.max2[k] += x
+	}
+	sg.min1[k] += x
+	if sg.min2[k] != math.MaxInt {
+		sg.min2[k] += x
+	}
+	sg.sum[k] += x * length
+	sg.lazy[k] += x
+}
+
+func (sg *segmentTreeBeats) push(k, l, r int) {
+	m := (l + r) / 2
+	if sg.lazy[k] != 0 {
+		sg.addNode(k*2, m-l, sg.lazy[k])
+		sg.addNode(k*2+1, r-m, sg.lazy[k])
+		sg.lazy[k] = 0
+	}
+	for _, c := range [2]int{k * 2, k*2 + 1} {
+		if sg.max1[k] < sg.max1[c] {
+			sg.chminNode(c, sg.max1[k])
+		}
+		if sg.min1[c] < sg.min1[k] {
+			sg.chmaxNode(c, sg.min1[k])
+		}
+	}
+}
+
+func (sg *segmentTreeBeats) update(k int) {
+	l, r := k*2, k*2+1
+	sg.sum[k] = sg.sum[l] + sg.sum[r]
+
+	switch {
+	case sg.max1[l] == sg.max1[r]:
+		sg.max1[k], sg.maxc[k] = sg.max1[l], sg.maxc[l]+sg.maxc[r]
+		sg.max2[k] = max(sg.max2[l], sg.max2[r])
+	case sg.max1[l] > sg.max1[r]:
+		sg.max1[k], sg.maxc[k] = sg.max1[l], sg.maxc[l]
+		sg.max2[k] = max(sg.max2[l], sg.max1[r])
+	default:
+		sg.max1[k], sg.maxc[k] = sg.max1[r], sg.maxc[r]
+		sg.max2[k] = max(sg.max1[l], sg.max2[r])
+	}
+
+	switch {
+	case sg.min1[l] == sg.min1[r]:
+		sg.min1[k], sg.minc[k] = sg.min1[l], sg.minc[l]+sg.minc[r]
+		sg.min2[k] = min(sg.min2[l], sg.min2[r])
+	case sg.min1[l] < sg.min1[r]:
+		sg.min1[k], sg.minc[k] = sg.min1[l], sg.minc[l]
+		sg.min2[k] = min(sg.min2[l], sg.min1[r])
+	default:
+		sg.min1[k], sg.minc[k] = sg.min1[r], sg.minc[r]
+		sg.min2[k] = min(sg.min1[l], sg.min2[r])
+	}
+}
+
+func (sg *segmentTreeBeats) checkInRange(i int) {
+	if i < 0 || sg.n <= i {
+		panic(fmt.Errorf("SegmentTreeBeats: index out of range: n=%d, i=%d", sg.n, i))
+	}
+}
+
+func (sg *segmentTreeBeats) checkInRangeLR(l, r int) {
+	if l < 0 || r < l || sg.n < r {
+		panic(fmt.Errorf("SegmentTreeBeats: index out of range: n=%d, l=%d, r=%d", sg.n, l, r))
+	}
+}
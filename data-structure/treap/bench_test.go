@@ -0,0 +1,111 @@
+package treap
+
+import (
+	"math/rand"
+	"testing"
+)
+
+const benchN = 1 << 17
+
+var benchBackends = []struct {
+	name string
+	new  func() OrderedMap[int, int]
+}{
+	{"Treap", func() OrderedMap[int, int] { return NewOrderedMap[int, int](WithSeed(1)) }},
+	{"BTree", NewBTreeMap[int, int]},
+	{"SortedList", NewSortedListMap[int, int]},
+}
+
+type benchOp int
+
+const (
+	benchSet benchOp = iota
+	benchDelete
+	benchGet
+	benchSearch
+	benchKth
+)
+
+type benchQuery struct {
+	op benchOp
+	k  int
+}
+
+// 割合がsetRatio:deleteRatio:queryRatioになるように操作列を作る
+// 問い合わせはGet, SearchRight, Kthを同じ割合で混ぜる
+func genBenchQueries(q, setRatio, deleteRatio, queryRatio int) []benchQuery {
+	rnd := rand.New(rand.NewSource(1))
+	res := make([]benchQuery, q)
+	for i := range res {
+		k := rnd.Intn(benchN * 4)
+		switch x := rnd.Intn(setRatio + deleteRatio + queryRatio); {
+		case x < setRatio:
+			res[i] = benchQuery{benchSet, k}
+		case x < setRatio+deleteRatio:
+			res[i] = benchQuery{benchDelete, k}
+		default:
+			res[i] = benchQuery{benchGet + benchOp(rnd.Intn(3)), k}
+		}
+	}
+	return res
+}
+
+func runBench(b *testing.B, init int, qs []benchQuery) {
+	for _, bk := range benchBackends {
+		b.Run(bk.name, func(b *testing.B) {
+			for range b.N {
+				b.StopTimer()
+				m := bk.new()
+				for i := range init {
+					m.Set(i*4, i)
+				}
+				b.StartTimer()
+				for _, q := range qs {
+					switch q.op {
+					case benchSet:
+						m.Set(q.k, q.k)
+					case benchDelete:
+						m.Delete(q.k)
+					case benchGet:
+						_, _ = m.Get(q.k)
+					case benchSearch:
+						_, _, _ = m.SearchRight(q.k, true)
+					case benchKth:
+						_, _, _ = m.Kth(q.k % (m.Len() + 1))
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkOrderedMap_InsertHeavy(b *testing.B) {
+	runBench(b, 0, genBenchQueries(benchN, 8, 1, 1))
+}
+
+func BenchmarkOrderedMap_QueryHeavy(b *testing.B) {
+	runBench(b, benchN, genBenchQueries(benchN, 1, 1, 8))
+}
+
+func BenchmarkOrderedMap_Mixed(b *testing.B) {
+	runBench(b, benchN/2, genBenchQueries(benchN, 1, 1, 1))
+}
+
+func BenchmarkOrderedMap_Ascend(b *testing.B) {
+	for _, bk := range benchBackends {
+		b.Run(bk.name, func(b *testing.B) {
+			m := bk.new()
+			for i := range benchN {
+				m.Set(i, i)
+			}
+			b.ResetTimer()
+			for range b.N {
+				sum := 0
+				m.Ascend(0, func(k, v int) bool {
+					sum += v
+					return true
+				})
+			}
+		})
+	}
+}
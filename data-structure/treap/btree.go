@@ -0,0 +1,577 @@
+package treap
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// 1ノードに持つキーの数は[btreeDegree-1, 2*btreeDegree-1] (根は除く)
+const btreeDegree = 32
+
+const (
+	btreeMinKeys = btreeDegree - 1
+	btreeMaxKeys = 2*btreeDegree - 1
+)
+
+// B木によるOrderedMapの実装
+// 1ノードに多くのキーを並べて持つので、treapよりポインタをたどる回数が少ない
+// JoinはO(log n)、Splitは移す要素数をmとしてO(m log n)
+func NewBTreeMap[K cmp.Ordered, V any]() OrderedMap[K, V] {
+	return NewBTreeMapFunc[K, V](cmp.Compare[K])
+}
+
+func NewBTreeMapFunc[K, V any](cmp func(x, y K) int) OrderedMap[K, V] {
+	return &bTree[K, V]{cmp: cmp}
+}
+
+// 参考にさせていただいたもの:
+// https://github.com/google/btree
+type bTree[K, V any] struct {
+	root *bTreeNode[K, V]
+	cmp  func(x, y K) int
+}
+
+type bTreeNode[K, V any] struct {
+	keys     []K
+	vals     []V
+	children []*bTreeNode[K, V] // 葉ならnil
+	size     int                // 部分木の要素数
+}
+
+func newBTreeNode[K, V any](leaf bool) *bTreeNode[K, V] {
+	nd := &bTreeNode[K, V]{
+		keys: make([]K, 0, btreeMaxKeys+1),
+		vals: make([]V, 0, btreeMaxKeys+1),
+	}
+	if !leaf {
+		nd.children = make([]*bTreeNode[K, V], 0, btreeMaxKeys+2)
+	}
+	return nd
+}
+
+func (nd *bTreeNode[K, V]) leaf() bool {
+	return nd.children == nil
+}
+
+// 葉の高さを1とする
+func (nd *bTreeNode[K, V]) height() int {
+	h := 1
+	for ; !nd.leaf(); nd = nd.children[0] {
+		h++
+	}
+	return h
+}
+
+func (nd *bTreeNode[K, V]) childSize(i int) int {
+	if nd.leaf() {
+		return 0
+	}
+	return nd.children[i].size
+}
+
+// keys[i]がkより大きい(upperがfalseならk以上)最初のiを返す
+func (bt *bTree[K, V]) bound(nd *bTreeNode[K, V], k K, upper bool) int {
+	lo, hi := 0, len(nd.keys)
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if c := bt.cmp(nd.keys[mid], k); c < 0 || upper && c == 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+func (bt *bTree[K, V]) find(nd *bTreeNode[K, V], k K) (int, bool) {
+	i := bt.bound(nd, k, false)
+	return i, i < len(nd.keys) && bt.cmp(nd.keys[i], k) == 0
+}
+
+func (bt *bTree[K, V]) Len() int {
+	if bt.root == nil {
+		return 0
+	}
+	return bt.root.size
+}
+
+func (bt *bTree[K, V]) Set(k K, v V) {
+	if bt.root == nil {
+		bt.root = newBTreeNode[K, V](true)
+	}
+	bt.insert(bt.root, k, v)
+	if len(bt.root.keys) > btreeMaxKeys {
+		root := newBTreeNode[K, V](false)
+		root.children = append(root.children, bt.root)
+		root.size = bt.root.size
+		bt.root = root
+		bt.splitChild(root, 0)
+	}
+}
+
+// 新しく挿入したらtrue 子のキーが多くなりすぎたら分割する
+func (bt *bTree[K, V]) insert(nd *bTreeNode[K, V], k K, v V) bool {
+	i, found := bt.find(nd, k)
+	if found {
+		nd.vals[i] = v
+		return false
+	}
+	if nd.leaf() {
+		nd.keys = insertAt(nd.keys, i, k)
+		nd.vals = insertAt(nd.vals, i, v)
+		nd.size++
+		return true
+	}
+	if !bt.insert(nd.children[i], k, v) {
+		return false
+	}
+	nd.size++
+	if len(nd.children[i].keys) > btreeMaxKeys {
+		bt.splitChild(nd, i)
+	}
+	return true
+}
+
+// p.children[i]を中央のキーで2つに分け、中央のキーをpに移す
+func (bt *bTree[K, V]) splitChild(p *bTreeNode[K, V], i int) {
+	c := p.children[i]
+	m := len(c.keys) / 2
+	r := newBTreeNode[K, V](c.leaf())
+	r.keys = append(r.keys, c.keys[m+1:]...)
+	r.vals = append(r.vals, c.vals[m+1:]...)
+	r.size = len(r.keys)
+	if !c.leaf() {
+		r.children = append(r.children, c.children[m+1:]...)
+		for _, ch := range r.children {
+			r.size += ch.size
+		}
+		clear(c.children[m+1:])
+		c.children = c.children[:m+1]
+	}
+	mk, mv := c.keys[m], c.vals[m]
+	clear(c.keys[m:])
+	clear(c.vals[m:])
+	c.keys, c.vals = c.keys[:m], c.vals[:m]
+	c.size -= r.size + 1
+
+	p.keys = insertAt(p.keys, i, mk)
+	p.vals = insertAt(p.vals, i, mv)
+	p.children = insertAt(p.children, i+1, r)
+}
+
+func (bt *bTree[K, V]) Get(k K) (V, bool) {
+	for nd := bt.root; nd != nil; {
+		i, found := bt.find(nd, k)
+		if found {
+			return nd.vals[i], true
+		}
+		if nd.leaf() {
+			break
+		}
+		nd = nd.children[i]
+	}
+	return *new(V), false
+}
+
+func (bt *bTree[K, V]) Delete(k K) {
+	if bt.root == nil || !bt.delete(bt.root, k) {
+		return
+	}
+	if len(bt.root.keys) == 0 {
+		if bt.root.leaf() {
+			bt.root = nil
+		} else {
+			bt.root = bt.root.children[0]
+		}
+	}
+}
+
+// 削除したらtrue 子のキーが少なくなりすぎたら兄弟から移すか併合する
+func (bt *bTree[K, V]) delete(nd *bTreeNode[K, V], k K) bool {
+	i, found := bt.find(nd, k)
+	if nd.leaf() {
+		if !found {
+			return false
+		}
+		nd.keys = deleteAt(nd.keys, i)
+		nd.vals = deleteAt(nd.vals, i)
+		nd.size--
+		return true
+	}
+	if found {
+		// 左の部分木の最大の要素で置き換える
+		nd.keys[i], nd.vals[i] = bt.deleteMax(nd.children[i])
+	} else if !bt.delete(nd.children[i], k) {
+		return false
+	}
+	nd.size--
+	bt.fixChild(nd, i)
+	return true
+}
+
+func (bt *bTree[K, V]) deleteMax(nd *bTreeNode[K, V]) (K, V) {
+	nd.size--
+	if nd.leaf() {
+		n := len(nd.keys) - 1
+		k, v := nd.keys[n], nd.vals[n]
+		nd.keys = deleteAt(nd.keys, n)
+		nd.vals = deleteAt(nd.vals, n)
+		return k, v
+	}
+	n := len(nd.children) - 1
+	k, v := bt.deleteMax(nd.children[n])
+	bt.fixChild(nd, n)
+	return k, v
+}
+
+func (bt *bTree[K, V]) fixChild(p *bTreeNode[K, V], i int) {
+	c := p.children[i]
+	if len(c.keys) >= btreeMinKeys {
+		return
+	}
+	if i > 0 && len(p.children[i-1].keys) > btreeMinKeys {
+		// 左の兄弟から1つ回してくる
+		l := p.children[i-1]
+		n := len(l.keys) - 1
+		c.keys = insertAt(c.keys, 0, p.keys[i-1])
+		c.vals = insertAt(c.vals, 0, p.vals[i-1])
+		p.keys[i-1], p.vals[i-1] = l.keys[n], l.vals[n]
+		l.keys = deleteAt(l.keys, n)
+		l.vals = deleteAt(l.vals, n)
+		moved := 1
+		if !l.leaf() {
+			ch := l.children[n+1]
+			l.children = deleteAt(l.children, n+1)
+			c.children = insertAt(c.children, 0, ch)
+			moved += ch.size
+		}
+		l.size -= moved
+		c.size += moved
+	} else if i+1 < len(p.children) && len(p.children[i+1].keys) > btreeMinKeys {
+		// 右の兄弟から1つ回してくる
+		r := p.children[i+1]
+		c.keys = append(c.keys, p.keys[i])
+		c.vals = append(c.vals, p.vals[i])
+		p.keys[i], p.vals[i] = r.keys[0], r.vals[0]
+		r.keys = deleteAt(r.keys, 0)
+		r.vals = deleteAt(r.vals, 0)
+		moved := 1
+		if !r.leaf() {
+			ch := r.children[0]
+			r.children = deleteAt(r.children, 0)
+			c.children = append(c.children, ch)
+			moved += ch.size
+		}
+		r.size -= moved
+		c.size += moved
+	} else if i+1 < len(p.children) {
+		bt.mergeChildren(p, i)
+	} else {
+		bt.mergeChildren(p, i-1)
+	}
+}
+
+// p.children[i], p.keys[i], p.children[i+1]を1つのノードにまとめる
+func (bt *bTree[K, V]) mergeChildren(p *bTreeNode[K, V], i int) {
+	l, r := p.children[i], p.children[i+1]
+	l.keys = append(append(l.keys, p.keys[i]), r.keys...)
+	l.vals = append(append(l.vals, p.vals[i]), r.vals...)
+	if !l.leaf() {
+		l.children = append(l.children, r.children...)
+	}
+	l.size += 1 + r.size
+	p.keys = deleteAt(p.keys, i)
+	p.vals = deleteAt(p.vals, i)
+	p.children = deleteAt(p.children, i+1)
+}
+
+func (bt *bTree[K, V]) Min() (K, V, bool) {
+	nd := bt.root
+	if nd == nil {
+		return *new(K), *new(V), false
+	}
+	for !nd.leaf() {
+		nd = nd.children[0]
+	}
+	return nd.keys[0], nd.vals[0], true
+}
+
+func (bt *bTree[K, V]) Max() (K, V, bool) {
+	nd := bt.root
+	if nd == nil {
+		return *new(K), *new(V), false
+	}
+	for !nd.leaf() {
+		nd = nd.children[len(nd.children)-1]
+	}
+	n := len(nd.keys) - 1
+	return nd.keys[n], nd.vals[n], true
+}
+
+// k以下かk未満な要素を探す
+// eqでイコールを許すかどうかを指定
+func (bt *bTree[K, V]) SearchLeft(k K, eq bool) (K, V, bool) {
+	var resK K
+	var resV V
+	ok := false
+	for nd := bt.root; nd != nil; {
+		i := bt.bound(nd, k, eq)
+		if i > 0 {
+			resK, resV, ok = nd.keys[i-1], nd.vals[i-1], true
+		}
+		if nd.leaf() {
+			break
+		}
+		nd = nd.children[i]
+	}
+	return resK, resV, ok
+}
+
+// k以上かkより大きい要素を探す
+// eqでイコールを許すかどうかを指定
+func (bt *bTree[K, V]) SearchRight(k K, eq bool) (K, V, bool) {
+	var resK K
+	var resV V
+	ok := false
+	for nd := bt.root; nd != nil; {
+		i := bt.bound(nd, k, !eq)
+		if i < len(nd.keys) {
+			resK, resV, ok = nd.keys[i], nd.vals[i], true
+		}
+		if nd.leaf() {
+			break
+		}
+		nd = nd.children[i]
+	}
+	return resK, resV, ok
+}
+
+func (bt *bTree[K, V]) Kth(i int) (K, V, bool) {
+	if i < 0 || bt.Len() <= i {
+		return *new(K), *new(V), false
+	}
+	nd := bt.root
+	for {
+		j := 0
+		for ; i >= nd.childSize(j); j++ {
+			i -= nd.childSize(j)
+			if i == 0 {
+				return nd.keys[j], nd.vals[j], true
+			}
+			i--
+		}
+		nd = nd.children[j]
+	}
+}
+
+func (bt *bTree[K, V]) Rank(k K) int {
+	res := 0
+	for nd := bt.root; nd != nil; {
+		i, found := bt.find(nd, k)
+		res += i
+		for j := range i {
+			res += nd.childSize(j)
+		}
+		if found {
+			return res + nd.childSize(i)
+		}
+		if nd.leaf() {
+			break
+		}
+		nd = nd.children[i]
+	}
+	return res
+}
+
+func (bt *bTree[K, V]) Ascend(from K, fn func(k K, v V) bool) {
+	bt.ascend(bt.root, from, fn)
+}
+
+func (bt *bTree[K, V]) ascend(nd *bTreeNode[K, V], from K, fn func(k K, v V) bool) bool {
+	if nd == nil {
+		return true
+	}
+	for i := bt.bound(nd, from, false); ; i++ {
+		if !nd.leaf() && !bt.ascend(nd.children[i], from, fn) {
+			return false
+		}
+		if i == len(nd.keys) {
+			return true
+		}
+		if !fn(nd.keys[i], nd.vals[i]) {
+			return false
+		}
+	}
+}
+
+func (bt *bTree[K, V]) Descend(from K, fn func(k K, v V) bool) {
+	bt.descend(bt.root, from, fn)
+}
+
+func (bt *bTree[K, V]) descend(nd *bTreeNode[K, V], from K, fn func(k K, v V) bool) bool {
+	if nd == nil {
+		return true
+	}
+	for i := bt.bound(nd, from, true); ; i-- {
+		if !nd.leaf() && !bt.descend(nd.children[i], from, fn) {
+			return false
+		}
+		if i == 0 {
+			return true
+		}
+		if !fn(nd.keys[i-1], nd.vals[i-1]) {
+			return false
+		}
+	}
+}
+
+func (bt *bTree[K, V]) Range(lo, hi K, fn func(k K, v V) bool) {
+	bt.Ascend(lo, func(k K, v V) bool {
+		return bt.cmp(k, hi) < 0 && fn(k, v)
+	})
+}
+
+func (bt *bTree[K, V]) Seek(k K) Cursor[K, V] {
+	k, _, ok := bt.SearchRight(k, true)
+	return newKeyCursor[K, V](bt, k, ok)
+}
+
+func (bt *bTree[K, V]) SeekFirst() Cursor[K, V] {
+	k, _, ok := bt.Min()
+	return newKeyCursor[K, V](bt, k, ok)
+}
+
+func (bt *bTree[K, V]) SeekLast() Cursor[K, V] {
+	k, _, ok := bt.Max()
+	return newKeyCursor[K, V](bt, k, ok)
+}
+
+func (bt *bTree[K, V]) Split(k K) OrderedMap[K, V] {
+	res := &bTree[K, V]{cmp: bt.cmp}
+	var ks []K
+	bt.Ascend(k, func(k K, v V) bool {
+		res.Set(k, v)
+		ks = append(ks, k)
+		return true
+	})
+	for _, k := range ks {
+		bt.Delete(k)
+	}
+	return res
+}
+
+func (bt *bTree[K, V]) Join(other OrderedMap[K, V]) {
+	o, ok := other.(*bTree[K, V])
+	if !ok {
+		panic(fmt.Errorf("OrderedMap: Join: other must be created by the same constructor"))
+	}
+	if kl, _, b := bt.Max(); b {
+		if kr, _, b := o.Min(); b && bt.cmp(kl, kr) >= 0 {
+			panic(fmt.Errorf("OrderedMap: Join: keys of other must be greater than all keys"))
+		}
+	}
+	if o.root == nil {
+		return
+	}
+	if bt.root == nil {
+		bt.root, o.root = o.root, nil
+		return
+	}
+	// otherの最小の要素を2つの木の間に置くキーとして使う
+	k, v, _ := o.Min()
+	o.Delete(k)
+	if o.root == nil {
+		bt.Set(k, v)
+		return
+	}
+	bt.root = bt.join(bt.root, k, v, o.root)
+	o.root = nil
+}
+
+// lの全てのキー < k < rの全てのキー のとき、l, k, rをつないだ木の根を返す
+// 高い方の木の端をたどり、低い方の木と同じ高さの部分木の隣にkと低い方の木を付ける
+func (bt *bTree[K, V]) join(l *bTreeNode[K, V], k K, v V, r *bTreeNode[K, V]) *bTreeNode[K, V] {
+	hl, hr := l.height(), r.height()
+	var root *bTreeNode[K, V]
+	switch {
+	case hl > hr:
+		bt.joinRight(l, hl, k, v, r, hr)
+		root = l
+	case hl < hr:
+		bt.joinLeft(r, hr, k, v, l, hl)
+		root = r
+	default:
+		root = newBTreeNode[K, V](false)
+		root.keys = append(root.keys, k)
+		root.vals = append(root.vals, v)
+		root.children = append(root.children, l, r)
+		root.size = l.size + 1 + r.size
+		for len(root.children) == 2 && len(root.children[0].keys) < btreeMinKeys {
+			bt.fixChild(root, 0)
+		}
+		for len(root.children) == 2 && len(root.children[1].keys) < btreeMinKeys {
+			bt.fixChild(root, 1)
+		}
+	}
+	if len(root.keys) > btreeMaxKeys {
+		nr := newBTreeNode[K, V](false)
+		nr.children = append(nr.children, root)
+		nr.size = root.size
+		bt.splitChild(nr, 0)
+		root = nr
+	}
+	if len(root.keys) == 0 {
+		root = root.children[0]
+	}
+	return root
+}
+
+// 高さhのndの右端に、高さhrの木rをkを挟んで付ける (h > hr)
+func (bt *bTree[K, V]) joinRight(nd *bTreeNode[K, V], h int, k K, v V, r *bTreeNode[K, V], hr int) {
+	nd.size += 1 + r.size
+	if h == hr+1 {
+		nd.keys = append(nd.keys, k)
+		nd.vals = append(nd.vals, v)
+		nd.children = append(nd.children, r)
+		for i := len(nd.children) - 1; i < len(nd.children) && len(nd.children[i].keys) < btreeMinKeys; {
+			bt.fixChild(nd, i)
+		}
+		return
+	}
+	i := len(nd.children) - 1
+	bt.joinRight(nd.children[i], h-1, k, v, r, hr)
+	if len(nd.children[i].keys) > btreeMaxKeys {
+		bt.splitChild(nd, i)
+	}
+}
+
+// 高さhのndの左端に、高さhlの木lをkを挟んで付ける (h > hl)
+func (bt *bTree[K, V]) joinLeft(nd *bTreeNode[K, V], h int, k K, v V, l *bTreeNode[K, V], hl int) {
+	nd.size += 1 + l.size
+	if h == hl+1 {
+		nd.keys = insertAt(nd.keys, 0, k)
+		nd.vals = insertAt(nd.vals, 0, v)
+		nd.children = insertAt(nd.children, 0, l)
+		for len(nd.children) > 1 && len(nd.children[0].keys) < btreeMinKeys {
+			bt.fixChild(nd, 0)
+		}
+		return
+	}
+	bt.joinLeft(nd.children[0], h-1, k, v, l, hl)
+	if len(nd.children[0].keys) > btreeMaxKeys {
+		bt.splitChild(nd, 0)
+	}
+}
+
+func insertAt[T any](s []T, i int, x T) []T {
+	s = append(s, x)
+	copy(s[i+1:], s[i:])
+	s[i] = x
+	return s
+}
+
+func deleteAt[T any](s []T, i int) []T {
+	copy(s[i:], s[i+1:])
+	clear(s[len(s)-1:])
+	return s[:len(s)-1]
+}
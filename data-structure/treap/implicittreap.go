@@ -0,0 +1,347 @@
+package treap
+
+import "fmt"
+
+// 添字をキーとして扱う列
+// 挿入・削除・区間反転・区間積・区間作用がO(log n)でできる
+type ImplicitTreap[S, F any] interface {
+	Len() int
+	InsertAt(i int, x S)
+	// i番目の要素を削除して返す
+	EraseAt(i int) S
+	Set(i int, x S)
+	Get(i int) S
+	Product(l, r int) S
+	Apply(i int, f F)
+	ApplyRange(l, r int, f F)
+	// [l, r)を反転する
+	Reverse(l, r int)
+	// [l, r)をm番目の要素が先頭に来るように回転する
+	Rotate(l, m, r int)
+	Slice() []S
+}
+
+// 引数の意味はsqdecomp.NewRangeSqrtDecompositionと同じ
+// mappingBlockは部分木の積への作用に使う nilならmappingを使う
+// 区間反転を使う場合、productは可換でなくてもよい
+func NewImplicitTreap[S, F any](
+	e func() S,
+	product func(x, y S) S,
+	id func() F,
+	mapping func(f F, x S) S,
+	mappingBlock func(f F, x S) S,
+	composition func(f, g F) F,
+	opts ...Option,
+) ImplicitTreap[S, F] {
+	return NewImplicitTreapWith(nil, e, product, id, mapping, mappingBlock, composition, opts...)
+}
+
+// O(n)で構築する
+func NewImplicitTreapWith[S, F any](
+	data []S,
+	e func() S,
+	product func(x, y S) S,
+	id func() F,
+	mapping func(f F, x S) S,
+	mappingBlock func(f F, x S) S,
+	composition func(f, g F) F,
+	opts ...Option,
+) ImplicitTreap[S, F] {
+	if mappingBlock == nil {
+		mappingBlock = mapping
+	}
+	it := &implicitTreap[S, F]{
+		e:            e,
+		product:      product,
+		id:           id,
+		mapping:      mapping,
+		mappingBlock: mappingBlock,
+		composition:  composition,
+		rnd:          newOptions(opts).rnd,
+	}
+	it.root = it.build(data)
+	return it
+}
+
+// split/mergeで操作するtreap
+// 参考にさせていただいた記事:
+// https://www.slideshare.net/iwiwi/2-12188757
+type implicitTreap[S, F any] struct {
+	root *implicitNode[S, F]
+
+	e       func() S
+	product func(x, y S) S
+
+	id           func() F
+	mapping      func(f F, x S) S
+	mappingBlock func(f F, x S) S
+	composition  func(f, g F) F
+
+	rnd Rand
+}
+
+type implicitNode[S, F any] struct {
+	val      S
+	prod     S // 部分木の積
+	rev      S // 部分木を逆順にした積
+	lazy     F
+	hasLazy  bool // 子に伝播していないlazyがあるかどうか
+	reversed bool // 子に伝播していない反転があるかどうか
+	priority uint64
+	size     int
+	l, r     *implicitNode[S, F]
+}
+
+func (it *implicitTreap[S, F]) Len() int {
+	return it.size(it.root)
+}
+
+func (it *implicitTreap[S, F]) InsertAt(i int, x S) {
+	it.checkInRangeLR(i, i)
+	a, b := it.split(it.root, i)
+	it.root = it.merge(it.merge(a, it.newNode(x)), b)
+}
+
+func (it *implicitTreap[S, F]) EraseAt(i int) S {
+	it.checkInRange(i)
+	a, b, c := it.split3(i, i+1)
+	it.root = it.merge(a, c)
+	return b.val
+}
+
+func (it *implicitTreap[S, F]) Set(i int, x S) {
+	it.checkInRange(i)
+	a, b, c := it.split3(i, i+1)
+	b.val = x
+	it.update(b)
+	it.root = it.merge(it.merge(a, b), c)
+}
+
+func (it *implicitTreap[S, F]) Get(i int) S {
+	it.checkInRange(i)
+	now := it.root
+	for {
+		it.push(now)
+		switch ls := it.size(now.l); {
+		case i < ls:
+			now = now.l
+		case i == ls:
+			return now.val
+		default:
+			i -= ls + 1
+			now = now.r
+		}
+	}
+}
+
+func (it *implicitTreap[S, F]) Product(l, r int) S {
+	it.checkInRangeLR(l, r)
+	a, b, c := it.split3(l, r)
+	res := it.prod(b)
+	it.root = it.merge(it.merge(a, b), c)
+	return res
+}
+
+func (it *implicitTreap[S, F]) Apply(i int, f F) {
+	it.checkInRange(i)
+	it.ApplyRange(i, i+1, f)
+}
+
+func (it *implicitTreap[S, F]) ApplyRange(l, r int, f F) {
+	it.checkInRangeLR(l, r)
+	a, b, c := it.split3(l, r)
+	it.apply(b, f)
+	it.root = it.merge(it.merge(a, b), c)
+}
+
+func (it *implicitTreap[S, F]) Reverse(l, r int) {
+	it.checkInRangeLR(l, r)
+	a, b, c := it.split3(l, r)
+	it.toggle(b)
+	it.root = it.merge(it.merge(a, b), c)
+}
+
+func (it *implicitTreap[S, F]) Rotate(l, m, r int) {
+	it.checkInRangeLR(l, r)
+	if m < l || r < m {
+		panic(fmt.Errorf("ImplicitTreap: invalid rotate: l=%d, m=%d, r=%d", l, m, r))
+	}
+	a, bc := it.split(it.root, l)
+	b, cd := it.split(bc, m-l)
+	c, d := it.split(cd, r-m)
+	it.root = it.merge(it.merge(a, c), it.merge(b, d))
+}
+
+func (it *implicitTreap[S, F]) Slice() []S {
+	res := make([]S, 0, it.Len())
+	var dfs func(nd *implicitNode[S, F])
+	dfs = func(nd *implicitNode[S, F]) {
+		if nd == nil {
+			return
+		}
+		it.push(nd)
+		dfs(nd.l)
+		res = append(res, nd.val)
+		dfs(nd.r)
+	}
+	dfs(it.root)
+	return res
+}
+
+func (it *implicitTreap[S, F]) newNode(x S) *implicitNode[S, F] {
+	return &implicitNode[S, F]{
+		val:      x,
+		prod:     x,
+		rev:      x,
+		lazy:     it.id(),
+		priority: it.rnd.Uint64(),
+		size:     1,
+	}
+}
+
+// 優先度をヒープ順に並べ直して、スタックで右端を管理しながら構築する
+func (it *implicitTreap[S, F]) build(data []S) *implicitNode[S, F] {
+	stack := make([]*implicitNode[S, F], 0)
+	for _, x := range data {
+		nd := it.newNode(x)
+		var last *implicitNode[S, F]
+		for len(stack) > 0 && stack[len(stack)-1].priority < nd.priority {
+			last = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			it.update(last)
+		}
+		nd.l = last
+		if len(stack) > 0 {
+			stack[len(stack)-1].r = nd
+		}
+		stack = append(stack, nd)
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		it.update(stack[i])
+	}
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[0]
+}
+
+// 先頭k個とそれ以外に分ける
+func (it *implicitTreap[S, F]) split(nd *implicitNode[S, F], k int) (*implicitNode[S, F], *implicitNode[S, F]) {
+	if nd == nil {
+		return nil, nil
+	}
+	it.push(nd)
+	if k <= it.size(nd.l) {
+		a, b := it.split(nd.l, k)
+		nd.l = b
+		it.update(nd)
+		return a, nd
+	}
+	a, b := it.split(nd.r, k-it.size(nd.l)-1)
+	nd.r = a
+	it.update(nd)
+	return nd, b
+}
+
+// [0, l), [l, r), [r, n)に分ける
+func (it *implicitTreap[S, F]) split3(l, r int) (*implicitNode[S, F], *implicitNode[S, F], *implicitNode[S, F]) {
+	a, bc := it.split(it.root, l)
+	b, c := it.split(bc, r-l)
+	return a, b, c
+}
+
+func (it *implicitTreap[S, F]) merge(a, b *implicitNode[S, F]) *implicitNode[S, F] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		it.push(a)
+		a.r = it.merge(a.r, b)
+		it.update(a)
+		return a
+	}
+	it.push(b)
+	b.l = it.merge(a, b.l)
+	it.update(b)
+	return b
+}
+
+func (it *implicitTreap[S, F]) size(nd *implicitNode[S, F]) int {
+	if nd == nil {
+		return 0
+	}
+	return nd.size
+}
+
+func (it *implicitTreap[S, F]) prod(nd *implicitNode[S, F]) S {
+	if nd == nil {
+		return it.e()
+	}
+	return nd.prod
+}
+
+func (it *implicitTreap[S, F]) revProd(nd *implicitNode[S, F]) S {
+	if nd == nil {
+		return it.e()
+	}
+	return nd.rev
+}
+
+func (it *implicitTreap[S, F]) update(nd *implicitNode[S, F]) {
+	nd.size = it.size(nd.l) + 1 + it.size(nd.r)
+	nd.prod = it.product(it.product(it.prod(nd.l), nd.val), it.prod(nd.r))
+	nd.rev = it.product(it.product(it.revProd(nd.r), nd.val), it.revProd(nd.l))
+}
+
+func (it *implicitTreap[S, F]) apply(nd *implicitNode[S, F], f F) {
+	if nd == nil {
+		return
+	}
+	nd.val = it.mapping(f, nd.val)
+	nd.prod = it.mappingBlock(f, nd.prod)
+	nd.rev = it.mappingBlock(f, nd.rev)
+	if nd.hasLazy {
+		nd.lazy = it.composition(nd.lazy, f)
+	} else {
+		nd.lazy = f
+	}
+	nd.hasLazy = true
+}
+
+func (it *implicitTreap[S, F]) toggle(nd *implicitNode[S, F]) {
+	if nd == nil {
+		return
+	}
+	nd.l, nd.r = nd.r, nd.l
+	nd.prod, nd.rev = nd.rev, nd.prod
+	nd.reversed = !nd.reversed
+}
+
+func (it *implicitTreap[S, F]) push(nd *implicitNode[S, F]) {
+	if nd.reversed {
+		it.toggle(nd.l)
+		it.toggle(nd.r)
+		nd.reversed = false
+	}
+	if nd.hasLazy {
+		it.apply(nd.l, nd.lazy)
+		it.apply(nd.r, nd.lazy)
+		nd.lazy = it.id()
+		nd.hasLazy = false
+	}
+}
+
+func (it *implicitTreap[S, F]) checkInRange(i int) {
+	if n := it.Len(); i < 0 || n <= i {
+		panic(fmt.Errorf("ImplicitTreap: index out of range: n=%d, i=%d", n, i))
+	}
+}
+
+func (it *implicitTreap[S, F]) checkInRangeLR(l, r int) {
+	if n := it.Len(); l < 0 || r < l || n < r {
+		panic(fmt.Errorf("ImplicitTreap: index out of range: n=%d, l=%d, r=%d", n, l, r))
+	}
+}
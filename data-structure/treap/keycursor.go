@@ -0,0 +1,55 @@
+package treap
+
+import "fmt"
+
+// キーで位置を覚えるカーソル ノードを持たない実装(BTreeMap, SortedListMap)で使う
+// 移動のたびにOrderedMapを探索し直すので、他の要素への挿入・削除があっても有効なまま使える
+type keyCursor[K, V any] struct {
+	m     OrderedMap[K, V]
+	k     K
+	valid bool
+}
+
+func newKeyCursor[K, V any](m OrderedMap[K, V], k K, valid bool) *keyCursor[K, V] {
+	return &keyCursor[K, V]{m, k, valid}
+}
+
+func (c *keyCursor[K, V]) Valid() bool {
+	return c.valid
+}
+
+func (c *keyCursor[K, V]) Key() K {
+	c.checkValid()
+	return c.k
+}
+
+func (c *keyCursor[K, V]) Value() V {
+	c.checkValid()
+	v, _ := c.m.Get(c.k)
+	return v
+}
+
+func (c *keyCursor[K, V]) Next() {
+	if c.valid {
+		c.k, _, c.valid = c.m.SearchRight(c.k, false)
+	}
+}
+
+func (c *keyCursor[K, V]) Prev() {
+	if c.valid {
+		c.k, _, c.valid = c.m.SearchLeft(c.k, false)
+	}
+}
+
+func (c *keyCursor[K, V]) Delete() {
+	c.checkValid()
+	del := c.k
+	c.Next()
+	c.m.Delete(del)
+}
+
+func (c *keyCursor[K, V]) checkValid() {
+	if !c.valid {
+		panic(fmt.Errorf("Cursor: cursor does not point to any element"))
+	}
+}
@@ -0,0 +1,106 @@
+package treap
+
+import "cmp"
+
+// 値がモノイドをなすOrderedMap
+// キーの区間に含まれる要素の値の積を求められる
+type MonoidOrderedMap[K, V any] interface {
+	OrderedMap[K, V]
+
+	// キーが[lo, hi)の要素の値をキーの昇順に掛けたもの
+	RangeProduct(lo, hi K) V
+	ProductAll() V
+	// キーがlo以上の要素の値を昇順に掛けていき、初めてpred(積)がfalseになった要素のキーを返す
+	// 最後までtrueならfalseを返す
+	// predはpred(e())==trueかつ単調である必要がある
+	MaxRight(lo K, pred func(prod V) bool) (K, bool)
+}
+
+func NewMonoidOrderedMap[K cmp.Ordered, V any](e func() V, op func(a, b V) V, opts ...Option) MonoidOrderedMap[K, V] {
+	return NewMonoidOrderedMapFunc[K, V](cmp.Compare[K], e, op, opts...)
+}
+
+func NewMonoidOrderedMapFunc[K, V any](cmp func(x, y K) int, e func() V, op func(a, b V) V, opts ...Option) MonoidOrderedMap[K, V] {
+	tr := newTreap[K, V](cmp, opts)
+	tr.e = e
+	tr.op = op
+	return tr
+}
+
+func (tr *treap[K, V]) RangeProduct(lo, hi K) V {
+	now := tr.root
+	for now != nil {
+		if tr.cmp(now.k, lo) < 0 {
+			now = now.childR
+		} else if tr.cmp(now.k, hi) >= 0 {
+			now = now.childL
+		} else {
+			break
+		}
+	}
+	if now == nil {
+		return tr.e()
+	}
+	// nowで左右に分かれる
+	res := tr.e()
+	for l := now.childL; l != nil; {
+		if tr.cmp(l.k, lo) >= 0 {
+			res = tr.op(tr.op(l.v, tr.subtreeProd(l.childR)), res)
+			l = l.childL
+		} else {
+			l = l.childR
+		}
+	}
+	res = tr.op(res, now.v)
+	for r := now.childR; r != nil; {
+		if tr.cmp(r.k, hi) < 0 {
+			res = tr.op(res, tr.op(tr.subtreeProd(r.childL), r.v))
+			r = r.childR
+		} else {
+			r = r.childL
+		}
+	}
+	return res
+}
+
+func (tr *treap[K, V]) ProductAll() V {
+	return tr.subtreeProd(tr.root)
+}
+
+// 木の形は変えない
+// キーがlo以上の要素は、lo以上の最初のノードから親をたどったときに
+// 左の子から上がってきたノードごとに (そのノード, その右部分木) の順に並ぶ
+func (tr *treap[K, V]) MaxRight(lo K, pred func(prod V) bool) (K, bool) {
+	acc := tr.e()
+	for nd := tr.lowerBound(lo); nd != nil; {
+		s := tr.op(acc, nd.v)
+		if !pred(s) {
+			return nd.k, true
+		}
+		acc = s
+		if s = tr.op(acc, tr.subtreeProd(nd.childR)); !pred(s) {
+			return tr.maxRightIn(nd.childR, acc, pred), true
+		}
+		acc = s
+		for nd.parent != nil && nd == nd.parent.childR {
+			nd = nd.parent
+		}
+		nd = nd.parent
+	}
+	return *new(K), false
+}
+
+// accにndの部分木の値を昇順に掛けていき、初めてpredがfalseになったノードのキーを返す
+// 部分木全体を掛けるとfalseになる必要がある
+func (tr *treap[K, V]) maxRightIn(nd *treapNode[K, V], acc V, pred func(prod V) bool) K {
+	for {
+		if s := tr.op(acc, tr.subtreeProd(nd.childL)); !pred(s) {
+			nd = nd.childL
+		} else if s = tr.op(s, nd.v); !pred(s) {
+			return nd.k
+		} else {
+			acc = s
+			nd = nd.childR
+		}
+	}
+}
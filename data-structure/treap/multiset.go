@@ -0,0 +1,243 @@
+package treap
+
+import (
+	"cmp"
+	"math"
+)
+
+// キーの重複を許すSet
+type Multiset[K any] interface {
+	Len() int
+	Insert(k K)
+	// kを1つだけ削除する
+	DeleteOne(k K)
+	// kを全て削除する
+	DeleteAll(k K)
+	// kの個数を返す
+	Count(k K) int
+	Min() (K, bool)
+	Max() (K, bool)
+
+	// k以下かk未満な要素を探す
+	// eqでイコールを許すかどうかを指定
+	SearchLeft(k K, eq bool) (K, bool)
+	// k以上かkより大きい要素を探す
+	// eqでイコールを許すかどうかを指定
+	SearchRight(k K, eq bool) (K, bool)
+
+	// 小さい方からi番目(0-indexed)の要素を返す
+	Kth(i int) (K, bool)
+	// k未満の要素の数を返す
+	Rank(k K) int
+}
+
+func NewMultiset[K cmp.Ordered](opts ...Option) Multiset[K] {
+	return NewMultisetFunc(cmp.Compare[K], opts...)
+}
+
+func NewMultisetFunc[K any](cmp func(x, y K) int, opts ...Option) Multiset[K] {
+	return &multiset[K]{NewMultiMapFunc[K, struct{}](cmp, opts...)}
+}
+
+type multiset[K any] struct {
+	m MultiMap[K, struct{}]
+}
+
+func (ms *multiset[K]) Len() int {
+	return ms.m.Len()
+}
+
+func (ms *multiset[K]) Insert(k K) {
+	ms.m.Insert(k, struct{}{})
+}
+
+func (ms *multiset[K]) DeleteOne(k K) {
+	ms.m.DeleteOne(k)
+}
+
+func (ms *multiset[K]) DeleteAll(k K) {
+	ms.m.DeleteAll(k)
+}
+
+func (ms *multiset[K]) Count(k K) int {
+	return ms.m.Count(k)
+}
+
+func (ms *multiset[K]) Min() (K, bool) {
+	k, _, b := ms.m.Min()
+	return k, b
+}
+
+func (ms *multiset[K]) Max() (K, bool) {
+	k, _, b := ms.m.Max()
+	return k, b
+}
+
+// k以下かk未満な要素を探す
+// eqでイコールを許すかどうかを指定
+func (ms *multiset[K]) SearchLeft(k K, eq bool) (K, bool) {
+	k, _, b := ms.m.SearchLeft(k, eq)
+	return k, b
+}
+
+// k以上かkより大きい要素を探す
+// eqでイコールを許すかどうかを指定
+func (ms *multiset[K]) SearchRight(k K, eq bool) (K, bool) {
+	k, _, b := ms.m.SearchRight(k, eq)
+	return k, b
+}
+
+func (ms *multiset[K]) Kth(i int) (K, bool) {
+	k, _, b := ms.m.Kth(i)
+	return k, b
+}
+
+func (ms *multiset[K]) Rank(k K) int {
+	return ms.m.Rank(k)
+}
+
+// キーの重複を許すOrderedMap
+// 同じキーの要素同士は挿入した順に並ぶ
+type MultiMap[K, V any] interface {
+	Len() int
+	Insert(k K, v V)
+	// キーがkの要素のうち、最初に挿入されたものの値を返す
+	Get(k K) (V, bool)
+	// キーがkの要素のうち、最初に挿入されたものを1つだけ削除する
+	DeleteOne(k K)
+	// キーがkの要素を全て削除する
+	DeleteAll(k K)
+	// キーがkの要素の個数を返す
+	Count(k K) int
+	Min() (K, V, bool)
+	Max() (K, V, bool)
+
+	// k以下かk未満な要素を探す
+	// eqでイコールを許すかどうかを指定
+	SearchLeft(k K, eq bool) (K, V, bool)
+	// k以上かkより大きい要素を探す
+	// eqでイコールを許すかどうかを指定
+	SearchRight(k K, eq bool) (K, V, bool)
+
+	// キーが小さい方からi番目(0-indexed)の要素を返す
+	Kth(i int) (K, V, bool)
+	// キーがk未満の要素の数を返す
+	Rank(k K) int
+}
+
+func NewMultiMap[K cmp.Ordered, V any](opts ...Option) MultiMap[K, V] {
+	return NewMultiMapFunc[K, V](cmp.Compare[K], opts...)
+}
+
+func NewMultiMapFunc[K, V any](cmp func(x, y K) int, opts ...Option) MultiMap[K, V] {
+	return &multiMap[K, V]{
+		m: NewOrderedMapFunc[multiKey[K], V](func(x, y multiKey[K]) int {
+			if c := cmp(x.k, y.k); c != 0 {
+				return c
+			}
+			if x.id < y.id {
+				return -1
+			} else if x.id > y.id {
+				return 1
+			}
+			return 0
+		}, opts...),
+		cmp:    cmp,
+		nextID: 1,
+	}
+}
+
+// 挿入順の通し番号をキーに付けて、重複の無いOrderedMapに載せる
+type multiMap[K, V any] struct {
+	m      OrderedMap[multiKey[K], V]
+	cmp    func(x, y K) int
+	nextID uint64 // 1から振る 0とmath.MaxUint64は探索用
+}
+
+type multiKey[K any] struct {
+	k  K
+	id uint64
+}
+
+// キーがkの要素のうち最初のものより前
+func lowest[K any](k K) multiKey[K] { return multiKey[K]{k, 0} }
+
+// キーがkの要素のうち最後のものより後
+func highest[K any](k K) multiKey[K] { return multiKey[K]{k, math.MaxUint64} }
+
+func (mm *multiMap[K, V]) Len() int {
+	return mm.m.Len()
+}
+
+func (mm *multiMap[K, V]) Insert(k K, v V) {
+	mm.m.Set(multiKey[K]{k, mm.nextID}, v)
+	mm.nextID++
+}
+
+func (mm *multiMap[K, V]) Get(k K) (V, bool) {
+	if mk, v, b := mm.m.SearchRight(lowest(k), true); b && mm.cmp(mk.k, k) == 0 {
+		return v, true
+	}
+	return *new(V), false
+}
+
+func (mm *multiMap[K, V]) DeleteOne(k K) {
+	if mk, _, b := mm.m.SearchRight(lowest(k), true); b && mm.cmp(mk.k, k) == 0 {
+		mm.m.Delete(mk)
+	}
+}
+
+func (mm *multiMap[K, V]) DeleteAll(k K) {
+	for {
+		mk, _, b := mm.m.SearchRight(lowest(k), true)
+		if !b || mm.cmp(mk.k, k) != 0 {
+			return
+		}
+		mm.m.Delete(mk)
+	}
+}
+
+func (mm *multiMap[K, V]) Count(k K) int {
+	return mm.m.Rank(highest(k)) - mm.m.Rank(lowest(k))
+}
+
+func (mm *multiMap[K, V]) Min() (K, V, bool) {
+	mk, v, b := mm.m.Min()
+	return mk.k, v, b
+}
+
+func (mm *multiMap[K, V]) Max() (K, V, bool) {
+	mk, v, b := mm.m.Max()
+	return mk.k, v, b
+}
+
+// k以下かk未満な要素を探す
+// eqでイコールを許すかどうかを指定
+func (mm *multiMap[K, V]) SearchLeft(k K, eq bool) (K, V, bool) {
+	key := lowest(k)
+	if eq {
+		key = highest(k)
+	}
+	mk, v, b := mm.m.SearchLeft(key, false)
+	return mk.k, v, b
+}
+
+// k以上かkより大きい要素を探す
+// eqでイコールを許すかどうかを指定
+func (mm *multiMap[K, V]) SearchRight(k K, eq bool) (K, V, bool) {
+	key := highest(k)
+	if eq {
+		key = lowest(k)
+	}
+	mk, v, b := mm.m.SearchRight(key, false)
+	return mk.k, v, b
+}
+
+func (mm *multiMap[K, V]) Kth(i int) (K, V, bool) {
+	mk, v, b := mm.m.Kth(i)
+	return mk.k, v, b
+}
+
+func (mm *multiMap[K, V]) Rank(k K) int {
+	return mm.m.Rank(lowest(k))
+}
@@ -0,0 +1,65 @@
+package treap
+
+import "time"
+
+// 優先度の生成に使う乱数生成器 *math/rand.Randもこれを満たす
+type Rand interface {
+	Uint64() uint64
+}
+
+// このパッケージのコンストラクタに渡すオプション
+type Option func(*options)
+
+type options struct {
+	rnd Rand
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.rnd == nil {
+		o.rnd = NewXorshift(uint64(time.Now().UnixNano()))
+	}
+	return o
+}
+
+// シードを固定する 同じシードなら同じ操作列に対して同じ形の木になるので、テストの再現に使える
+func WithSeed(seed uint64) Option {
+	return func(o *options) {
+		o.rnd = NewXorshift(seed)
+	}
+}
+
+// 乱数生成器を指定する
+func WithRand(rnd Rand) Option {
+	return func(o *options) {
+		o.rnd = rnd
+	}
+}
+
+// xorshift64
+// 参考: https://ja.wikipedia.org/wiki/Xorshift
+type Xorshift struct {
+	x uint64
+}
+
+func NewXorshift(seed uint64) *Xorshift {
+	// 状態が0だと0しか出なくなるので、splitmix64で混ぜてから使う
+	z := seed + 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z ^= z >> 31
+	if z == 0 {
+		z = 1
+	}
+	return &Xorshift{z}
+}
+
+func (xs *Xorshift) Uint64() uint64 {
+	xs.x ^= xs.x << 13
+	xs.x ^= xs.x >> 7
+	xs.x ^= xs.x << 17
+	return xs.x
+}
@@ -0,0 +1,316 @@
+package treap
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+)
+
+// バケットの大きさの下限 要素数が少ないうちはこの大きさまで1つのバケットに入れる
+const sortedListMinLoad = 64
+
+// ソート済みのスライス(バケット)を並べたものによるOrderedMapの実装
+// バケットの大きさと数をO(√n)に保つので、挿入・削除はならしO(√n)、探索はO(log n)
+// Kth/RankはO(√n)、SplitはO(√n)、JoinはならしO(√n)
+func NewSortedListMap[K cmp.Ordered, V any]() OrderedMap[K, V] {
+	return NewSortedListMapFunc[K, V](cmp.Compare[K])
+}
+
+func NewSortedListMapFunc[K, V any](cmp func(x, y K) int) OrderedMap[K, V] {
+	return &sortedList[K, V]{cmp: cmp}
+}
+
+// 参考にさせていただいたもの:
+// https://github.com/tatyam-prime/SortedSet
+type sortedList[K, V any] struct {
+	keys [][]K // 空のバケットは持たない
+	vals [][]V
+	len  int
+	cmp  func(x, y K) int
+}
+
+// バケットの大きさがこれを超えたら2つに分ける
+func (sl *sortedList[K, V]) maxLoad() int {
+	return 2 * max(sortedListMinLoad, int(math.Sqrt(float64(sl.len))))
+}
+
+// 削除で小さなバケットが増えると、バケットの数がO(√n)でなくなる
+// バケットが多すぎたら、大きさmax(sortedListMinLoad, √n)のバケットに詰め直す
+// 詰め直した後にこうなるまでには、要素がΩ(n)個減る必要がある
+func (sl *sortedList[K, V]) rebalance() {
+	load := max(sortedListMinLoad, int(math.Sqrt(float64(sl.len))))
+	if len(sl.keys) <= 4*(sl.len/load+1) {
+		return
+	}
+	keys := make([][]K, 0, (sl.len+load-1)/load)
+	vals := make([][]V, 0, cap(keys))
+	var bk []K
+	var bv []V
+	for b := range sl.keys {
+		for i := range sl.keys[b] {
+			if len(bk) == load {
+				keys, vals = append(keys, bk), append(vals, bv)
+				bk, bv = nil, nil
+			}
+			bk, bv = append(bk, sl.keys[b][i]), append(bv, sl.vals[b][i])
+		}
+	}
+	if len(bk) > 0 {
+		keys, vals = append(keys, bk), append(vals, bv)
+	}
+	sl.keys, sl.vals = keys, vals
+}
+
+// kより大きい(upperがfalseならk以上)最初の要素の位置を(バケット, バケット内の添字)で返す
+// そのような要素がなければ(len(sl.keys), 0)を返す
+func (sl *sortedList[K, V]) locate(k K, upper bool) (int, int) {
+	less := func(x K) bool {
+		c := sl.cmp(x, k)
+		return c < 0 || upper && c == 0
+	}
+	lo, hi := 0, len(sl.keys)
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if b := sl.keys[mid]; less(b[len(b)-1]) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo == len(sl.keys) {
+		return lo, 0
+	}
+	b := lo
+	lo, hi = 0, len(sl.keys[b])
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if less(sl.keys[b][mid]) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return b, lo
+}
+
+// (b, i)の1つ前の位置
+func (sl *sortedList[K, V]) prevPos(b, i int) (int, int, bool) {
+	if i > 0 {
+		return b, i - 1, true
+	}
+	if b > 0 {
+		return b - 1, len(sl.keys[b-1]) - 1, true
+	}
+	return 0, 0, false
+}
+
+func (sl *sortedList[K, V]) find(k K) (int, int, bool) {
+	b, i := sl.locate(k, false)
+	return b, i, b < len(sl.keys) && sl.cmp(sl.keys[b][i], k) == 0
+}
+
+func (sl *sortedList[K, V]) at(b, i int) (K, V, bool) {
+	return sl.keys[b][i], sl.vals[b][i], true
+}
+
+func (sl *sortedList[K, V]) Len() int {
+	return sl.len
+}
+
+func (sl *sortedList[K, V]) Set(k K, v V) {
+	if sl.len == 0 {
+		sl.keys = append(sl.keys[:0], []K{k})
+		sl.vals = append(sl.vals[:0], []V{v})
+		sl.len = 1
+		return
+	}
+	b, i, found := sl.find(k)
+	if found {
+		sl.vals[b][i] = v
+		return
+	}
+	if b == len(sl.keys) {
+		b--
+		i = len(sl.keys[b])
+	}
+	sl.keys[b] = insertAt(sl.keys[b], i, k)
+	sl.vals[b] = insertAt(sl.vals[b], i, v)
+	sl.len++
+	if n := len(sl.keys[b]); n > sl.maxLoad() {
+		m := n / 2
+		sl.keys = insertAt(sl.keys, b+1, append([]K(nil), sl.keys[b][m:]...))
+		sl.vals = insertAt(sl.vals, b+1, append([]V(nil), sl.vals[b][m:]...))
+		clear(sl.keys[b][m:])
+		clear(sl.vals[b][m:])
+		sl.keys[b], sl.vals[b] = sl.keys[b][:m], sl.vals[b][:m]
+	}
+}
+
+func (sl *sortedList[K, V]) Get(k K) (V, bool) {
+	if b, i, found := sl.find(k); found {
+		return sl.vals[b][i], true
+	}
+	return *new(V), false
+}
+
+func (sl *sortedList[K, V]) Delete(k K) {
+	b, i, found := sl.find(k)
+	if !found {
+		return
+	}
+	sl.keys[b] = deleteAt(sl.keys[b], i)
+	sl.vals[b] = deleteAt(sl.vals[b], i)
+	sl.len--
+	if len(sl.keys[b]) == 0 {
+		sl.keys = deleteAt(sl.keys, b)
+		sl.vals = deleteAt(sl.vals, b)
+	}
+	sl.rebalance()
+}
+
+func (sl *sortedList[K, V]) Min() (K, V, bool) {
+	if sl.len == 0 {
+		return *new(K), *new(V), false
+	}
+	return sl.at(0, 0)
+}
+
+func (sl *sortedList[K, V]) Max() (K, V, bool) {
+	if sl.len == 0 {
+		return *new(K), *new(V), false
+	}
+	b := len(sl.keys) - 1
+	return sl.at(b, len(sl.keys[b])-1)
+}
+
+// k以下かk未満な要素を探す
+// eqでイコールを許すかどうかを指定
+func (sl *sortedList[K, V]) SearchLeft(k K, eq bool) (K, V, bool) {
+	if b, i, ok := sl.prevPos(sl.locate(k, eq)); ok {
+		return sl.at(b, i)
+	}
+	return *new(K), *new(V), false
+}
+
+// k以上かkより大きい要素を探す
+// eqでイコールを許すかどうかを指定
+func (sl *sortedList[K, V]) SearchRight(k K, eq bool) (K, V, bool) {
+	if b, i := sl.locate(k, !eq); b < len(sl.keys) {
+		return sl.at(b, i)
+	}
+	return *new(K), *new(V), false
+}
+
+func (sl *sortedList[K, V]) Kth(i int) (K, V, bool) {
+	if i < 0 || sl.len <= i {
+		return *new(K), *new(V), false
+	}
+	b := 0
+	for i >= len(sl.keys[b]) {
+		i -= len(sl.keys[b])
+		b++
+	}
+	return sl.at(b, i)
+}
+
+func (sl *sortedList[K, V]) Rank(k K) int {
+	b, i := sl.locate(k, false)
+	for _, bk := range sl.keys[:b] {
+		i += len(bk)
+	}
+	return i
+}
+
+func (sl *sortedList[K, V]) Ascend(from K, fn func(k K, v V) bool) {
+	b, i := sl.locate(from, false)
+	for ; b < len(sl.keys); b, i = b+1, 0 {
+		for ; i < len(sl.keys[b]); i++ {
+			if !fn(sl.keys[b][i], sl.vals[b][i]) {
+				return
+			}
+		}
+	}
+}
+
+func (sl *sortedList[K, V]) Descend(from K, fn func(k K, v V) bool) {
+	b, i, ok := sl.prevPos(sl.locate(from, true))
+	if !ok {
+		return
+	}
+	for ; b >= 0; b-- {
+		if i < 0 {
+			i = len(sl.keys[b]) - 1
+		}
+		for ; i >= 0; i-- {
+			if !fn(sl.keys[b][i], sl.vals[b][i]) {
+				return
+			}
+		}
+	}
+}
+
+func (sl *sortedList[K, V]) Range(lo, hi K, fn func(k K, v V) bool) {
+	sl.Ascend(lo, func(k K, v V) bool {
+		return sl.cmp(k, hi) < 0 && fn(k, v)
+	})
+}
+
+func (sl *sortedList[K, V]) Seek(k K) Cursor[K, V] {
+	k, _, ok := sl.SearchRight(k, true)
+	return newKeyCursor[K, V](sl, k, ok)
+}
+
+func (sl *sortedList[K, V]) SeekFirst() Cursor[K, V] {
+	k, _, ok := sl.Min()
+	return newKeyCursor[K, V](sl, k, ok)
+}
+
+func (sl *sortedList[K, V]) SeekLast() Cursor[K, V] {
+	k, _, ok := sl.Max()
+	return newKeyCursor[K, V](sl, k, ok)
+}
+
+func (sl *sortedList[K, V]) Split(k K) OrderedMap[K, V] {
+	b, i := sl.locate(k, false)
+	res := &sortedList[K, V]{cmp: sl.cmp}
+	if b == len(sl.keys) {
+		return res
+	}
+	if i > 0 {
+		// バケットbの途中で分ける
+		res.keys = append(res.keys, append([]K(nil), sl.keys[b][i:]...))
+		res.vals = append(res.vals, append([]V(nil), sl.vals[b][i:]...))
+		clear(sl.keys[b][i:])
+		clear(sl.vals[b][i:])
+		sl.keys[b], sl.vals[b] = sl.keys[b][:i], sl.vals[b][:i]
+		b++
+	}
+	res.keys = append(res.keys, sl.keys[b:]...)
+	res.vals = append(res.vals, sl.vals[b:]...)
+	clear(sl.keys[b:])
+	clear(sl.vals[b:])
+	sl.keys, sl.vals = sl.keys[:b], sl.vals[:b]
+	for _, bk := range res.keys {
+		res.len += len(bk)
+	}
+	sl.len -= res.len
+	sl.rebalance()
+	return res
+}
+
+func (sl *sortedList[K, V]) Join(other OrderedMap[K, V]) {
+	o, ok := other.(*sortedList[K, V])
+	if !ok {
+		panic(fmt.Errorf("OrderedMap: Join: other must be created by the same constructor"))
+	}
+	if kl, _, b := sl.Max(); b {
+		if kr, _, b := o.Min(); b && sl.cmp(kl, kr) >= 0 {
+			panic(fmt.Errorf("OrderedMap: Join: keys of other must be greater than all keys"))
+		}
+	}
+	sl.keys = append(sl.keys, o.keys...)
+	sl.vals = append(sl.vals, o.vals...)
+	sl.len += o.len
+	o.keys, o.vals, o.len = nil, nil, 0
+	sl.rebalance()
+}
@@ -2,8 +2,7 @@ package treap
 
 import (
 	"cmp"
-	"math/rand"
-	"time"
+	"fmt"
 )
 
 type Set[K any] interface {
@@ -20,9 +19,38 @@ type Set[K any] interface {
 	// k以上かkより大きい要素を探す
 	// eqでイコールを許すかどうかを指定
 	SearchRight(k K, eq bool) (K, bool)
+
+	// 小さい方からi番目(0-indexed)の要素を返す
+	Kth(i int) (K, bool)
+	// k未満の要素の数を返す
+	Rank(k K) int
+
+	// from以上の要素を昇順に列挙する fnがfalseを返したら止める
+	Ascend(from K, fn func(k K) bool)
+	// from以下の要素を降順に列挙する fnがfalseを返したら止める
+	Descend(from K, fn func(k K) bool)
+	// [lo, hi)の要素を昇順に列挙する fnがfalseを返したら止める
+	Range(lo, hi K, fn func(k K) bool)
+
+	// k以上の要素を切り離して返す
+	Split(k K) Set[K]
+	// otherの要素を全て移す otherの要素は全てこのSetの要素より大きい必要がある
+	// otherは空になる
+	Join(other Set[K])
+
+	// 以下の3つは、要素数の少ない方をm、多い方をnとしてO(m log(n/m + 1))
+	// otherは同じ比較関数を持つ必要があり、操作後は空になる
+	// otherが自分自身のときは、UnionとIntersectionは何もせず、Differenceは空にする
+
+	// otherの要素を全て加える
+	Union(other Set[K])
+	// otherにも含まれる要素だけを残す
+	Intersection(other Set[K])
+	// otherに含まれる要素を取り除く
+	Difference(other Set[K])
 }
 
-func NewSet[K cmp.Ordered]() Set[K] {
+func NewSet[K cmp.Ordered](opts ...Option) Set[K] {
 	return NewSetFunc(func(x, y K) int {
 		if x < y {
 			return -1
@@ -30,15 +58,15 @@ func NewSet[K cmp.Ordered]() Set[K] {
 			return 1
 		}
 		return 0
-	})
+	}, opts...)
 }
 
-func NewSetFunc[K any](cmp func(x, y K) int) Set[K] {
-	return &set[K]{NewOrderedMapFunc[K, struct{}](cmp)}
+func NewSetFunc[K any](cmp func(x, y K) int, opts ...Option) Set[K] {
+	return &set[K]{newTreap[K, struct{}](cmp, opts)}
 }
 
 type set[K any] struct {
-	m OrderedMap[K, struct{}]
+	m *treap[K, struct{}]
 }
 
 func (st *set[K]) Len() int {
@@ -82,6 +110,63 @@ func (st *set[K]) SearchRight(k K, eq bool) (K, bool) {
 	return k, b
 }
 
+func (st *set[K]) Kth(i int) (K, bool) {
+	k, _, b := st.m.Kth(i)
+	return k, b
+}
+
+func (st *set[K]) Rank(k K) int {
+	return st.m.Rank(k)
+}
+
+func (st *set[K]) Ascend(from K, fn func(k K) bool) {
+	st.m.Ascend(from, func(k K, _ struct{}) bool { return fn(k) })
+}
+
+func (st *set[K]) Descend(from K, fn func(k K) bool) {
+	st.m.Descend(from, func(k K, _ struct{}) bool { return fn(k) })
+}
+
+func (st *set[K]) Range(lo, hi K, fn func(k K) bool) {
+	st.m.Range(lo, hi, func(k K, _ struct{}) bool { return fn(k) })
+}
+
+func (st *set[K]) Split(k K) Set[K] {
+	return &set[K]{st.m.Split(k).(*treap[K, struct{}])}
+}
+
+func (st *set[K]) Join(other Set[K]) {
+	st.m.Join(other.(*set[K]).m)
+}
+
+func (st *set[K]) Union(other Set[K]) {
+	o := other.(*set[K]).m
+	if o == st.m {
+		return
+	}
+	st.m.setRoot(st.m.union(st.m.root, o.root))
+	o.setRoot(nil)
+}
+
+func (st *set[K]) Intersection(other Set[K]) {
+	o := other.(*set[K]).m
+	if o == st.m {
+		return
+	}
+	st.m.setRoot(st.m.intersection(st.m.root, o.root))
+	o.setRoot(nil)
+}
+
+func (st *set[K]) Difference(other Set[K]) {
+	o := other.(*set[K]).m
+	if o == st.m {
+		st.m.setRoot(nil)
+		return
+	}
+	st.m.setRoot(st.m.difference(st.m.root, o.root))
+	o.setRoot(nil)
+}
+
 type OrderedMap[K, V any] interface {
 	Len() int
 	Set(k K, v V)
@@ -96,9 +181,47 @@ type OrderedMap[K, V any] interface {
 	// k以上かkより大きい要素を探す
 	// eqでイコールを許すかどうかを指定
 	SearchRight(k K, eq bool) (K, V, bool)
+
+	// キーが小さい方からi番目(0-indexed)の要素を返す
+	Kth(i int) (K, V, bool)
+	// キーがk未満の要素の数を返す
+	Rank(k K) int
+
+	// キーがfrom以上の要素を昇順に列挙する fnがfalseを返したら止める
+	Ascend(from K, fn func(k K, v V) bool)
+	// キーがfrom以下の要素を降順に列挙する fnがfalseを返したら止める
+	Descend(from K, fn func(k K, v V) bool)
+	// キーが[lo, hi)の要素を昇順に列挙する fnがfalseを返したら止める
+	Range(lo, hi K, fn func(k K, v V) bool)
+
+	// キーがk以上の最初の要素を指すカーソルを返す
+	Seek(k K) Cursor[K, V]
+	// 最小の要素を指すカーソルを返す
+	SeekFirst() Cursor[K, V]
+	// 最大の要素を指すカーソルを返す
+	SeekLast() Cursor[K, V]
+
+	// キーがk以上の要素を切り離して返す
+	Split(k K) OrderedMap[K, V]
+	// otherの要素を全て移す otherのキーは全てこのOrderedMapのキーより大きい必要がある
+	// otherは空になる
+	Join(other OrderedMap[K, V])
+}
+
+// OrderedMapの要素を1つ指すカーソル
+// 指している要素以外への挿入・削除があっても有効なまま使える
+type Cursor[K, V any] interface {
+	// 要素を指しているかどうか 端を越えて移動するとfalseになる
+	Valid() bool
+	Key() K
+	Value() V
+	Next()
+	Prev()
+	// 指している要素を削除して、次の要素に移動する
+	Delete()
 }
 
-func NewOrderedMap[K cmp.Ordered, V any]() OrderedMap[K, V] {
+func NewOrderedMap[K cmp.Ordered, V any](opts ...Option) OrderedMap[K, V] {
 	return NewOrderedMapFunc[K, V](func(x, y K) int {
 		if x < y {
 			return -1
@@ -106,44 +229,110 @@ func NewOrderedMap[K cmp.Ordered, V any]() OrderedMap[K, V] {
 			return 1
 		}
 		return 0
-	})
+	}, opts...)
+}
+
+func NewOrderedMapFunc[K, V any](cmp func(x, y K) int, opts ...Option) OrderedMap[K, V] {
+	return newTreap[K, V](cmp, opts)
 }
 
-func NewOrderedMapFunc[K, V any](cmp func(x, y K) int) OrderedMap[K, V] {
+func newTreap[K, V any](cmp func(x, y K) int, opts []Option) *treap[K, V] {
 	tr := &treap[K, V]{
 		0,
 		nil,
 		cmp,
-		rand.New(rand.NewSource(time.Now().UnixNano())),
+		newOptions(opts).rnd,
+		nil,
+		nil,
+		&nodePool[K, V]{},
 	}
 	return tr
 }
 
-// キーの重複を許さない仕様
+// キーの重複を許さない仕様 重複させたい場合はMultiMapを使う
 type treap[K, V any] struct {
 	len  int
 	root *treapNode[K, V]
 	cmp  func(x, y K) int
-	rnd  *rand.Rand
+	rnd  Rand
+
+	// MonoidOrderedMapとして使う場合のみ設定する
+	e  func() V
+	op func(a, b V) V
+
+	pool *nodePool[K, V]
+}
+
+// ノードをまとめて確保し、削除したノードを使い回す
+// Set/Deleteを大量に繰り返してもGCの負担が増えないようにする
+type nodePool[K, V any] struct {
+	free  *treapNode[K, V] // 削除済みノードのリスト parentでつなぐ
+	chunk []treapNode[K, V]
+}
+
+func (pl *nodePool[K, V]) get() *treapNode[K, V] {
+	if nd := pl.free; nd != nil {
+		pl.free = nd.parent
+		return nd
+	}
+	if len(pl.chunk) == 0 {
+		pl.chunk = make([]treapNode[K, V], 256)
+	}
+	nd := &pl.chunk[0]
+	pl.chunk = pl.chunk[1:]
+	return nd
+}
+
+func (pl *nodePool[K, V]) put(nd *treapNode[K, V]) {
+	*nd = treapNode[K, V]{}
+	nd.parent = pl.free
+	pl.free = nd
 }
 
 type treapNode[K, V any] struct {
 	k                      K
 	v                      V
+	prod                   V      // 部分木の値の積 MonoidOrderedMapとして使う場合のみ計算する
 	priority               uint64 // 優先度が高いノードを根に近い位置に置く
+	size                   int    // 部分木のノード数
 	parent, childL, childR *treapNode[K, V]
 }
 
 func (tr *treap[K, V]) newTreapNode(k K, v V, parent, childL, childR *treapNode[K, V]) *treapNode[K, V] {
-	nd := &treapNode[K, V]{
+	nd := tr.pool.get()
+	*nd = treapNode[K, V]{
 		k,
 		v,
+		v,
 		tr.rnd.Uint64(),
+		1,
 		parent, childL, childR,
 	}
 	return nd
 }
 
+func (nd *treapNode[K, V]) subtreeSize() int {
+	if nd == nil {
+		return 0
+	}
+	return nd.size
+}
+
+// 子の情報からndの部分木の情報を計算し直す
+func (tr *treap[K, V]) update(nd *treapNode[K, V]) {
+	nd.size = 1 + nd.childL.subtreeSize() + nd.childR.subtreeSize()
+	if tr.op != nil {
+		nd.prod = tr.op(tr.op(tr.subtreeProd(nd.childL), nd.v), tr.subtreeProd(nd.childR))
+	}
+}
+
+func (tr *treap[K, V]) subtreeProd(nd *treapNode[K, V]) V {
+	if nd == nil {
+		return tr.e()
+	}
+	return nd.prod
+}
+
 // find returns (parent, child)
 func (tr *treap[K, V]) find(k K) (*treapNode[K, V], *treapNode[K, V]) {
 	var p *treapNode[K, V]
@@ -189,6 +378,8 @@ func (tr *treap[K, V]) rotate(p, c *treapNode[K, V]) {
 		}
 	}
 	p.parent = c
+	tr.update(p)
+	tr.update(c)
 }
 
 func (tr *treap[K, V]) prev(nd *treapNode[K, V]) (*treapNode[K, V], bool) {
@@ -244,6 +435,11 @@ func (tr *treap[K, V]) Set(k K, v V) {
 	par, now := tr.find(k)
 	if now != nil {
 		now.v = v
+		if tr.op != nil {
+			for a := now; a != nil; a = a.parent {
+				tr.update(a)
+			}
+		}
 		return
 	}
 	now = tr.newTreapNode(k, v, par, nil, nil)
@@ -253,6 +449,9 @@ func (tr *treap[K, V]) Set(k K, v V) {
 	} else {
 		par.childR = now
 	}
+	for a := par; a != nil; a = a.parent {
+		tr.update(a)
+	}
 	for par != nil && par.priority < now.priority {
 		tr.rotate(par, now)
 		par = now.parent
@@ -270,10 +469,12 @@ func (tr *treap[K, V]) Get(k K) (V, bool) {
 }
 
 func (tr *treap[K, V]) Delete(k K) {
-	_, now := tr.find(k)
-	if now == nil {
-		return
+	if _, now := tr.find(k); now != nil {
+		tr.deleteNode(now)
 	}
+}
+
+func (tr *treap[K, V]) deleteNode(now *treapNode[K, V]) {
 	for now.childL != nil || now.childR != nil {
 		switch {
 		case now.childL != nil && now.childR != nil:
@@ -295,10 +496,13 @@ func (tr *treap[K, V]) Delete(k K) {
 			now.parent.childR = nil
 		}
 	}
+	for a := now.parent; a != nil; a = a.parent {
+		tr.update(a)
+	}
 	if now == tr.root {
 		tr.root = nil
 	}
-	*now = treapNode[K, V]{}
+	tr.pool.put(now)
 	tr.len--
 }
 
@@ -330,7 +534,7 @@ func (tr *treap[K, V]) SearchLeft(k K, eq bool) (K, V, bool) {
 	par, now := tr.find(k)
 	if now != nil {
 		if eq {
-			return k, now.v, true
+			return now.k, now.v, true
 		} else if prev, b := tr.prev(now); b {
 			return prev.k, prev.v, true
 		}
@@ -350,7 +554,7 @@ func (tr *treap[K, V]) SearchRight(k K, eq bool) (K, V, bool) {
 	par, now := tr.find(k)
 	if now != nil {
 		if eq {
-			return k, now.v, true
+			return now.k, now.v, true
 		} else if next, b := tr.next(now); b {
 			return next.k, next.v, true
 		}
@@ -363,3 +567,298 @@ func (tr *treap[K, V]) SearchRight(k K, eq bool) (K, V, bool) {
 	}
 	return *new(K), *new(V), false
 }
+
+func (tr *treap[K, V]) Kth(i int) (K, V, bool) {
+	if i < 0 || tr.Len() <= i {
+		return *new(K), *new(V), false
+	}
+	now := tr.root
+	for {
+		switch ls := now.childL.subtreeSize(); {
+		case i < ls:
+			now = now.childL
+		case i == ls:
+			return now.k, now.v, true
+		default:
+			i -= ls + 1
+			now = now.childR
+		}
+	}
+}
+
+func (tr *treap[K, V]) Rank(k K) int {
+	res := 0
+	for now := tr.root; now != nil; {
+		if tr.cmp(k, now.k) <= 0 {
+			now = now.childL
+		} else {
+			res += now.childL.subtreeSize() + 1
+			now = now.childR
+		}
+	}
+	return res
+}
+
+func (tr *treap[K, V]) Ascend(from K, fn func(k K, v V) bool) {
+	for now := tr.lowerBound(from); now != nil; now, _ = tr.next(now) {
+		if !fn(now.k, now.v) {
+			return
+		}
+	}
+}
+
+func (tr *treap[K, V]) Descend(from K, fn func(k K, v V) bool) {
+	for now := tr.lastAtMost(from); now != nil; now, _ = tr.prev(now) {
+		if !fn(now.k, now.v) {
+			return
+		}
+	}
+}
+
+func (tr *treap[K, V]) Range(lo, hi K, fn func(k K, v V) bool) {
+	for now := tr.lowerBound(lo); now != nil && tr.cmp(now.k, hi) < 0; now, _ = tr.next(now) {
+		if !fn(now.k, now.v) {
+			return
+		}
+	}
+}
+
+func (tr *treap[K, V]) Seek(k K) Cursor[K, V] {
+	return &treapCursor[K, V]{tr, tr.lowerBound(k)}
+}
+
+func (tr *treap[K, V]) SeekFirst() Cursor[K, V] {
+	now := tr.root
+	for now != nil && now.childL != nil {
+		now = now.childL
+	}
+	return &treapCursor[K, V]{tr, now}
+}
+
+func (tr *treap[K, V]) SeekLast() Cursor[K, V] {
+	now := tr.root
+	for now != nil && now.childR != nil {
+		now = now.childR
+	}
+	return &treapCursor[K, V]{tr, now}
+}
+
+// キーがk以上の最初のノード
+func (tr *treap[K, V]) lowerBound(k K) *treapNode[K, V] {
+	var res *treapNode[K, V]
+	for now := tr.root; now != nil; {
+		if tr.cmp(k, now.k) <= 0 {
+			res = now
+			now = now.childL
+		} else {
+			now = now.childR
+		}
+	}
+	return res
+}
+
+// キーがk以下の最後のノード
+func (tr *treap[K, V]) lastAtMost(k K) *treapNode[K, V] {
+	var res *treapNode[K, V]
+	for now := tr.root; now != nil; {
+		if tr.cmp(k, now.k) >= 0 {
+			res = now
+			now = now.childR
+		} else {
+			now = now.childL
+		}
+	}
+	return res
+}
+
+// ノードのポインタを持つ 回転してもノード自体は動かないので、他の要素への操作で無効にならない
+type treapCursor[K, V any] struct {
+	tr  *treap[K, V]
+	now *treapNode[K, V]
+}
+
+func (c *treapCursor[K, V]) Valid() bool {
+	return c.now != nil
+}
+
+func (c *treapCursor[K, V]) Key() K {
+	c.checkValid()
+	return c.now.k
+}
+
+func (c *treapCursor[K, V]) Value() V {
+	c.checkValid()
+	return c.now.v
+}
+
+func (c *treapCursor[K, V]) Next() {
+	c.now, _ = c.tr.next(c.now)
+}
+
+func (c *treapCursor[K, V]) Prev() {
+	c.now, _ = c.tr.prev(c.now)
+}
+
+func (c *treapCursor[K, V]) Delete() {
+	c.checkValid()
+	del := c.now
+	c.now, _ = c.tr.next(c.now)
+	c.tr.deleteNode(del)
+}
+
+func (c *treapCursor[K, V]) checkValid() {
+	if c.now == nil {
+		panic(fmt.Errorf("Cursor: cursor does not point to any element"))
+	}
+}
+
+func (tr *treap[K, V]) Split(k K) OrderedMap[K, V] {
+	l, r := tr.split(tr.root, func(x K) bool { return tr.cmp(x, k) < 0 })
+	tr.setRoot(l)
+	res := &treap[K, V]{0, nil, tr.cmp, tr.rnd, tr.e, tr.op, tr.pool}
+	res.setRoot(r)
+	return res
+}
+
+func (tr *treap[K, V]) Join(other OrderedMap[K, V]) {
+	o, ok := other.(*treap[K, V])
+	if !ok {
+		panic(fmt.Errorf("OrderedMap: Join: other must be created by the same constructor"))
+	}
+	if kl, _, b := tr.Max(); b {
+		if kr, _, b := o.Min(); b && tr.cmp(kl, kr) >= 0 {
+			panic(fmt.Errorf("OrderedMap: Join: keys of other must be greater than all keys"))
+		}
+	}
+	tr.setRoot(tr.merge(tr.root, o.root))
+	o.setRoot(nil)
+}
+
+// 根をndにして、要素数を合わせる
+func (tr *treap[K, V]) setRoot(nd *treapNode[K, V]) {
+	if nd != nil {
+		nd.parent = nil
+	}
+	tr.root = nd
+	tr.len = nd.subtreeSize()
+}
+
+func (tr *treap[K, V]) setChildL(p, c *treapNode[K, V]) {
+	p.childL = c
+	if c != nil {
+		c.parent = p
+	}
+}
+
+func (tr *treap[K, V]) setChildR(p, c *treapNode[K, V]) {
+	p.childR = c
+	if c != nil {
+		c.parent = p
+	}
+}
+
+// left(k)がtrueになるキーとfalseになるキーに分ける leftは単調である必要がある
+// 返り値の根のparentは更新しないので、呼び出し側で設定する
+func (tr *treap[K, V]) split(nd *treapNode[K, V], left func(k K) bool) (*treapNode[K, V], *treapNode[K, V]) {
+	if nd == nil {
+		return nil, nil
+	}
+	if left(nd.k) {
+		a, b := tr.split(nd.childR, left)
+		tr.setChildR(nd, a)
+		tr.update(nd)
+		return nd, b
+	}
+	a, b := tr.split(nd.childL, left)
+	tr.setChildL(nd, b)
+	tr.update(nd)
+	return a, nd
+}
+
+// k未満, k, kより大きい に分ける 真ん中はノード1つかnil
+func (tr *treap[K, V]) split3(nd *treapNode[K, V], k K) (*treapNode[K, V], *treapNode[K, V], *treapNode[K, V]) {
+	l, mr := tr.split(nd, func(x K) bool { return tr.cmp(x, k) < 0 })
+	m, r := tr.split(mr, func(x K) bool { return tr.cmp(x, k) <= 0 })
+	return l, m, r
+}
+
+// aのキーは全てbのキーより小さい必要がある
+// 返り値の根のparentは更新しないので、呼び出し側で設定する
+func (tr *treap[K, V]) merge(a, b *treapNode[K, V]) *treapNode[K, V] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		tr.setChildR(a, tr.merge(a.childR, b))
+		tr.update(a)
+		return a
+	}
+	tr.setChildL(b, tr.merge(a, b.childL))
+	tr.update(b)
+	return b
+}
+
+// 同じキーがあればaのノードを残す
+func (tr *treap[K, V]) union(a, b *treapNode[K, V]) *treapNode[K, V] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority < b.priority {
+		l, m, r := tr.split3(a, b.k)
+		if m != nil {
+			b.k, b.v = m.k, m.v
+			tr.pool.put(m)
+		}
+		tr.setChildL(b, tr.union(l, b.childL))
+		tr.setChildR(b, tr.union(r, b.childR))
+		tr.update(b)
+		return b
+	}
+	l, m, r := tr.split3(b, a.k)
+	if m != nil {
+		tr.pool.put(m)
+	}
+	tr.setChildL(a, tr.union(a.childL, l))
+	tr.setChildR(a, tr.union(a.childR, r))
+	tr.update(a)
+	return a
+}
+
+func (tr *treap[K, V]) intersection(a, b *treapNode[K, V]) *treapNode[K, V] {
+	if a == nil || b == nil {
+		return nil
+	}
+	if a.priority < b.priority {
+		a, b = b, a
+	}
+	l, m, r := tr.split3(b, a.k)
+	cl := tr.intersection(a.childL, l)
+	cr := tr.intersection(a.childR, r)
+	if m == nil {
+		tr.pool.put(a)
+		return tr.merge(cl, cr)
+	}
+	tr.pool.put(m)
+	tr.setChildL(a, cl)
+	tr.setChildR(a, cr)
+	tr.update(a)
+	return a
+}
+
+// aからbのキーを取り除く
+func (tr *treap[K, V]) difference(a, b *treapNode[K, V]) *treapNode[K, V] {
+	if a == nil || b == nil {
+		return a
+	}
+	l, m, r := tr.split3(a, b.k)
+	if m != nil {
+		tr.pool.put(m)
+	}
+	return tr.merge(tr.difference(l, b.childL), tr.difference(r, b.childR))
+}
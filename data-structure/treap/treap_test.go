@@ -0,0 +1,236 @@
+package treap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"testing/quick"
+)
+
+// kだけで比較するキー tagは比較に使わないので、比較上等しくても中身が違うキーを作れる
+type testKey struct {
+	k, tag int
+}
+
+func compareTestKey(x, y testKey) int {
+	if x.k < y.k {
+		return -1
+	} else if x.k > y.k {
+		return 1
+	}
+	return 0
+}
+
+type testEntry struct {
+	k testKey
+	v int
+}
+
+// ソート済みスライスで作ったOrderedMapの参照実装
+type sortedModel struct {
+	s []testEntry
+}
+
+func (m *sortedModel) search(k testKey) (int, bool) {
+	return slices.BinarySearchFunc(m.s, k, func(e testEntry, k testKey) int { return compareTestKey(e.k, k) })
+}
+
+func (m *sortedModel) set(k testKey, v int) {
+	if i, ok := m.search(k); ok {
+		// 既存のキーは置き換えず、値だけ更新する
+		m.s[i].v = v
+	} else {
+		m.s = slices.Insert(m.s, i, testEntry{k, v})
+	}
+}
+
+func (m *sortedModel) delete(k testKey) {
+	if i, ok := m.search(k); ok {
+		m.s = slices.Delete(m.s, i, i+1)
+	}
+}
+
+func (m *sortedModel) at(i int) (testKey, int, bool) {
+	if i < 0 || len(m.s) <= i {
+		return testKey{}, 0, false
+	}
+	return m.s[i].k, m.s[i].v, true
+}
+
+func (m *sortedModel) searchLeft(k testKey, eq bool) (testKey, int, bool) {
+	i, ok := m.search(k)
+	if ok && eq {
+		return m.at(i)
+	}
+	return m.at(i - 1)
+}
+
+func (m *sortedModel) searchRight(k testKey, eq bool) (testKey, int, bool) {
+	i, ok := m.search(k)
+	if ok && !eq {
+		return m.at(i + 1)
+	}
+	return m.at(i)
+}
+
+type testResult struct {
+	k testKey
+	v int
+	b bool
+}
+
+// OrderedMapの実装ごとに同じテストを回す
+var testBackends = []struct {
+	name string
+	new  func(seed int64) OrderedMap[testKey, int]
+}{
+	{"Treap", func(seed int64) OrderedMap[testKey, int] {
+		return NewOrderedMapFunc[testKey, int](compareTestKey, WithSeed(uint64(seed)))
+	}},
+	{"BTree", func(int64) OrderedMap[testKey, int] { return NewBTreeMapFunc[testKey, int](compareTestKey) }},
+	{"SortedList", func(int64) OrderedMap[testKey, int] { return NewSortedListMapFunc[testKey, int](compareTestKey) }},
+}
+
+// seedから操作列を作り、OrderedMapと参照実装の結果を比べる
+func checkAgainstModel(t *testing.T, newMap func(seed int64) OrderedMap[testKey, int], seed int64, ops, keyRange int) bool {
+	t.Helper()
+	rnd := rand.New(rand.NewSource(seed))
+	m := newMap(seed)
+	model := &sortedModel{}
+	for step := range ops {
+		k := testKey{rnd.Intn(keyRange), rnd.Intn(1 << 20)}
+		eq := rnd.Intn(2) == 0
+		var got, want testResult
+		var op string
+		switch rnd.Intn(6) {
+		case 0, 1:
+			op = "Set"
+			v := rnd.Int()
+			m.Set(k, v)
+			model.set(k, v)
+		case 2:
+			op = "Delete"
+			m.Delete(k)
+			model.delete(k)
+		case 3:
+			op = "Min/Max"
+			got.k, got.v, got.b = m.Min()
+			want.k, want.v, want.b = model.at(0)
+			if got == want {
+				got.k, got.v, got.b = m.Max()
+				want.k, want.v, want.b = model.at(len(model.s) - 1)
+			}
+		case 4:
+			op = "SearchLeft"
+			got.k, got.v, got.b = m.SearchLeft(k, eq)
+			want.k, want.v, want.b = model.searchLeft(k, eq)
+		case 5:
+			op = "SearchRight"
+			got.k, got.v, got.b = m.SearchRight(k, eq)
+			want.k, want.v, want.b = model.searchRight(k, eq)
+		}
+		if got != want || m.Len() != len(model.s) {
+			t.Errorf("seed=%d step=%d %s(k=%v, eq=%v): got %+v (len %d), want %+v (len %d)",
+				seed, step, op, k, eq, got, m.Len(), want, len(model.s))
+			return false
+		}
+	}
+	return true
+}
+
+func TestOrderedMap_Model(t *testing.T) {
+	for _, bk := range testBackends {
+		t.Run(bk.name, func(t *testing.T) {
+			f := func(seed int64) bool {
+				return checkAgainstModel(t, bk.new, seed, 500, 64)
+			}
+			if err := quick.Check(f, &quick.Config{MaxCount: 200}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestOrderedMap_ModelDense(t *testing.T) {
+	// キーの種類を少なくして、既存キーへの操作を多くする
+	for _, bk := range testBackends {
+		t.Run(bk.name, func(t *testing.T) {
+			f := func(seed int64) bool {
+				return checkAgainstModel(t, bk.new, seed, 200, 4)
+			}
+			if err := quick.Check(f, &quick.Config{MaxCount: 200}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestOrderedMap_SearchEdgeCases(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup []testKey
+		k     testKey
+		eq    bool
+		left  testResult
+		right testResult
+	}{
+		{"empty", nil, testKey{1, 0}, true, testResult{}, testResult{}},
+		{"single/equal", []testKey{{5, 1}}, testKey{5, 2}, true, testResult{testKey{5, 1}, 0, true}, testResult{testKey{5, 1}, 0, true}},
+		{"single/equal strict", []testKey{{5, 1}}, testKey{5, 2}, false, testResult{}, testResult{}},
+		{"single/below", []testKey{{5, 1}}, testKey{4, 0}, true, testResult{}, testResult{testKey{5, 1}, 0, true}},
+		{"single/above", []testKey{{5, 1}}, testKey{6, 0}, true, testResult{testKey{5, 1}, 0, true}, testResult{}},
+		{"many/equal", []testKey{{1, 1}, {3, 3}, {5, 5}}, testKey{3, 9}, true, testResult{testKey{3, 3}, 0, true}, testResult{testKey{3, 3}, 0, true}},
+		{"many/equal strict", []testKey{{1, 1}, {3, 3}, {5, 5}}, testKey{3, 9}, false, testResult{testKey{1, 1}, 0, true}, testResult{testKey{5, 5}, 0, true}},
+		{"many/between", []testKey{{1, 1}, {3, 3}, {5, 5}}, testKey{4, 9}, false, testResult{testKey{3, 3}, 0, true}, testResult{testKey{5, 5}, 0, true}},
+		{"many/below min", []testKey{{1, 1}, {3, 3}, {5, 5}}, testKey{1, 9}, false, testResult{}, testResult{testKey{3, 3}, 0, true}},
+		{"many/above max", []testKey{{1, 1}, {3, 3}, {5, 5}}, testKey{5, 9}, false, testResult{testKey{3, 3}, 0, true}, testResult{}},
+	}
+	for _, bk := range testBackends {
+		for _, tt := range tests {
+			t.Run(bk.name+"/"+tt.name, func(t *testing.T) {
+				m := bk.new(1)
+				for _, k := range tt.setup {
+					m.Set(k, 0)
+				}
+				var got testResult
+				got.k, got.v, got.b = m.SearchLeft(tt.k, tt.eq)
+				if got != tt.left {
+					t.Errorf("SearchLeft(%v, %v) = %+v, want %+v", tt.k, tt.eq, got, tt.left)
+				}
+				got.k, got.v, got.b = m.SearchRight(tt.k, tt.eq)
+				if got != tt.right {
+					t.Errorf("SearchRight(%v, %v) = %+v, want %+v", tt.k, tt.eq, got, tt.right)
+				}
+			})
+		}
+	}
+}
+
+func TestSet_SelfOperand(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(s Set[int])
+		want []int
+	}{
+		{"Union", func(s Set[int]) { s.Union(s) }, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{"Intersection", func(s Set[int]) { s.Intersection(s) }, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{"Difference", func(s Set[int]) { s.Difference(s) }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSet[int]()
+			for i := range 10 {
+				s.Set(i)
+			}
+			tt.op(s)
+			var got []int
+			s.Ascend(0, func(k int) bool {
+				got = append(got, k)
+				return true
+			})
+			if !slices.Equal(got, tt.want) || s.Len() != len(tt.want) {
+				t.Errorf("got %v (Len %d), want %v", got, s.Len(), tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/ynm3n/go-cplib
 
-go 1.22.0
+go 1.23.0
 
 require (
 	github.com/google/go-cmp v0.5.8